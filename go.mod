@@ -5,11 +5,11 @@ go 1.23.0
 toolchain go1.23.3
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0
+	github.com/pkg/sftp v1.13.9
 	github.com/sirupsen/logrus v1.9.3
 	golang.org/x/crypto v0.41.0
+	golang.org/x/sys v0.35.0
 )
 
-require (
-	github.com/stretchr/testify v1.8.0 // indirect
-	golang.org/x/sys v0.35.0 // indirect
-)
+require github.com/kr/fs v0.1.0 // indirect
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -17,11 +18,15 @@ type Config struct {
 	SourceReplaceFrom string            `json:"sourceReplaceFrom"` // Optional: Source path prefix to strip (e.g., "/data/Movies")
 	SourceReplaceTo   string            `json:"sourceReplaceTo"`   // Optional: Local path replacement (e.g., "/media/source"). Leave empty for same-volume mounting
 	DestRootDir       string            `json:"destRootDir"`       // Required: Destination root path (e.g., "/mnt/data/Movies")
-	TransferMethod    string            `json:"transferMethod"`    // Optional: Force transfer method ("rsync" or "scp"), auto-detected if empty
+	TransferMethod    string            `json:"transferMethod"`    // Optional: Force transfer method ("rsync", "scp" or "local"), auto-detected if empty
+	TransferDirection string            `json:"transferDirection"` // "push" (run beside the source) or "pull" (run beside the destination, fetch over SSH)
+	StateDir          string            `json:"stateDir"`          // Optional: Directory for persistent state (indexes, caches). Kept in memory only if empty
 	Interval          time.Duration     `json:"interval"`
 	SSH               SSHConfig         `json:"ssh"`
 	Performance       PerformanceConfig `json:"performance"`
 	Transfer          TransferConfig    `json:"transfer"`
+	Bandwidth         BandwidthConfig   `json:"bandwidth"`
+	Metadata          MetadataConfig    `json:"metadata"`
 	DryRun            bool              `json:"dryRun"`
 	LogLevel          string            `json:"logLevel"`
 }
@@ -36,16 +41,38 @@ type PlexServerConfig struct {
 	Token        string `json:"token"`
 	Protocol     string `json:"protocol"` // http/https
 	RequireHTTPS bool   `json:"requireHttps"`
+	// BandwidthLimit caps transfers to this server in bytes per second (0 = unlimited)
+	BandwidthLimit int64 `json:"bandwidthLimit,omitempty"`
 }
 
 // SSHConfig represents SSH connection configuration
 type SSHConfig struct {
+	Host               string `json:"host"` // SSH target for file operations; defaults to the destination Plex host
 	User               string `json:"user"`
 	Password           string `json:"password"`
 	Port               string `json:"port"`
 	KeyPath            string `json:"keyPath,omitempty"`        // Optional, for future key-based auth
 	StrictHostKeyCheck bool   `json:"strictHostKeyCheck"`       // Whether to enforce host key verification
 	KnownHostsFile     string `json:"knownHostsFile,omitempty"` // Path to known_hosts file
+	// Connection health: keepalives detect dead links, which are redialed with backoff
+	KeepaliveInterval time.Duration `json:"keepaliveInterval"`   // 0 disables keepalive requests
+	ReconnectAttempts int           `json:"reconnectAttempts"`   // Dial attempts before an operation fails
+	PoolSize          int           `json:"poolSize"`            // Connections shared round-robin by concurrent operations
+	JumpHosts         []JumpHost    `json:"jumpHosts,omitempty"` // Optional bastion chain, dialed in order before the destination
+}
+
+// JumpHost is one hop of an SSH jump host (ProxyJump) chain with its own credentials
+type JumpHost struct {
+	User     string `json:"user"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Password string `json:"password,omitempty"`
+	KeyPath  string `json:"keyPath,omitempty"`
+}
+
+// Address returns the host:port to dial for the hop
+func (j JumpHost) Address() string {
+	return net.JoinHostPort(j.Host, j.Port)
 }
 
 // PerformanceConfig represents performance-related configuration
@@ -58,8 +85,119 @@ type PerformanceConfig struct {
 
 // TransferConfig represents transfer-related configuration
 type TransferConfig struct {
-	EnableCompression bool `json:"enableCompression"`
-	ResumeTransfers   bool `json:"resumeTransfers"`
+	EnableCompression bool   `json:"enableCompression"`
+	ResumeTransfers   bool   `json:"resumeTransfers"`
+	VerifyChecksums   bool   `json:"verifyChecksums"`   // Verify transferred (and same-size) files by comparing content hashes
+	ChecksumAlgorithm string `json:"checksumAlgorithm"` // Hash used for verification ("sha256", "md5" or "xxh64")
+	// ProgressLogInterval is how often transfer progress is logged during long transfers (0 disables)
+	ProgressLogInterval time.Duration `json:"progressLogInterval"`
+	MinFreeSpace        int64         `json:"minFreeSpace"`     // Bytes to keep free on the destination
+	SpacePolicy         string        `json:"spacePolicy"`      // "partial" (transfer what fits) or "abort" (transfer nothing) when space is short
+	TransferPriority    string        `json:"transferPriority"` // Item order: "default", "smallest", "largest", "newest", "oldest"
+	// LinkMode creates hardlinks or reflinks instead of copying when source and destination share a filesystem
+	LinkMode       string `json:"linkMode"`       // "off", "hardlink", "reflink" or "auto" (reflink, then hardlink)
+	LinkSourceRoot string `json:"linkSourceRoot"` // Remote linking: path on the destination host that mirrors DEST_ROOT_DIR for the source library
+}
+
+// MetadataConfig represents metadata and playback state synchronization settings
+type MetadataConfig struct {
+	// ProgressMinDelta is the smallest resume position difference worth syncing; smaller gaps are left alone
+	// so a progress update doesn't bounce back and forth between the servers
+	ProgressMinDelta time.Duration `json:"progressMinDelta"`
+	// SyncHomeUsers also syncs watched state and progress of Plex Home / managed users, not just the server owner
+	SyncHomeUsers bool              `json:"syncHomeUsers"`
+	UserMap       map[string]string `json:"userMap,omitempty"` // Lowercased source user name -> destination user name; unmapped users match by name
+	// Policies holds the sync direction and conflict rule of each field group (FieldGroup* constants)
+	Policies map[string]SyncPolicy `json:"policies"`
+	// Playlists holds the lowercased titles of source playlists to sync; playlists carrying the sync label are synced too
+	Playlists map[string]bool `json:"playlists,omitempty"`
+	// LockFields holds the fields (LockableFields) locked whenever SyncArr writes them, so agent refreshes don't
+	// undo the sync. A field locked on the sending server is locked on the receiving one regardless.
+	LockFields map[string]bool `json:"lockFields"`
+	// ForceRefresh forces a metadata refresh of the destination libraries after each library scan
+	ForceRefresh bool `json:"forceRefresh"`
+	// SyncMarkers copies intro and credits markers to destination items that have none
+	SyncMarkers bool `json:"syncMarkers"`
+}
+
+// LockableFields are the metadata fields SyncArr writes, named as Plex names them in field locks
+var LockableFields = []string{
+	"title", "titleSort", "originalTitle", "summary", "tagline", "contentRating", "studio", "originallyAvailableAt", "editionTitle",
+	"label", "genre", "collection", "director", "writer", "producer", "country", "thumb", "art", "theme",
+}
+
+// Metadata field groups that each have their own sync policy
+const (
+	FieldGroupWatched     = "watched"
+	FieldGroupProgress    = "progress"
+	FieldGroupRating      = "rating"
+	FieldGroupLabels      = "labels" // Labels and genres
+	FieldGroupCollections = "collections"
+	FieldGroupText        = "text"     // Title, sort title, summary and the other single-value fields
+	FieldGroupCredits     = "credits"  // Directors, writers, producers and countries
+	FieldGroupSettings    = "settings" // Per-item settings such as useOriginalTitle or episode sorting
+	FieldGroupStreams     = "streams"  // Selected audio and subtitle streams
+	FieldGroupArtwork     = "artwork"
+)
+
+// Sync directions of a field group
+const (
+	DirectionSourceToDest  = "source-to-dest"
+	DirectionDestToSource  = "dest-to-source"
+	DirectionBidirectional = "bidirectional"
+	DirectionOff           = "off"
+)
+
+// Conflict rules, applied when both servers changed a field since it was last synced
+const (
+	ConflictNewest = "newest" // The most recently updated server wins
+	ConflictSource = "source" // The source server wins
+	ConflictUnion  = "union"  // Tag lists are merged (labels, collections and credits only)
+)
+
+// SyncPolicy is the direction and conflict rule of one metadata field group
+type SyncPolicy struct {
+	Direction string `json:"direction"`
+	Conflict  string `json:"conflict"`
+}
+
+// DefaultSyncPolicies returns the policy of every field group when SYNC_POLICY_<GROUP> is not set
+func DefaultSyncPolicies() map[string]SyncPolicy {
+	return map[string]SyncPolicy{
+		FieldGroupWatched:     {Direction: DirectionBidirectional, Conflict: ConflictNewest},
+		FieldGroupProgress:    {Direction: DirectionBidirectional, Conflict: ConflictNewest},
+		FieldGroupRating:      {Direction: DirectionSourceToDest, Conflict: ConflictSource},
+		FieldGroupLabels:      {Direction: DirectionSourceToDest, Conflict: ConflictSource},
+		FieldGroupCollections: {Direction: DirectionOff, Conflict: ConflictSource},
+		FieldGroupText:        {Direction: DirectionOff, Conflict: ConflictSource},
+		FieldGroupCredits:     {Direction: DirectionOff, Conflict: ConflictSource},
+		FieldGroupSettings:    {Direction: DirectionOff, Conflict: ConflictSource},
+		FieldGroupStreams:     {Direction: DirectionSourceToDest, Conflict: ConflictSource},
+		FieldGroupArtwork:     {Direction: DirectionSourceToDest, Conflict: ConflictSource},
+	}
+}
+
+// BandwidthConfig represents global bandwidth limiting and transfer time windows
+type BandwidthConfig struct {
+	Limit    int64             `json:"limit"`    // Global cap in bytes per second (0 = unlimited)
+	Schedule []BandwidthWindow `json:"schedule"` // Optional time windows overriding the global cap
+}
+
+// BandwidthWindow is a daily time window with its own bandwidth cap. Windows may span midnight.
+type BandwidthWindow struct {
+	Start int   `json:"start"` // Minutes since midnight (inclusive)
+	End   int   `json:"end"`   // Minutes since midnight (exclusive)
+	Limit int64 `json:"limit"` // Cap in bytes per second (0 = unlimited)
+	Off   bool  `json:"off"`   // No transfers at all during this window
+}
+
+// Contains reports whether the given minute of the day falls inside the window
+func (w BandwidthWindow) Contains(minuteOfDay int) bool {
+	if w.Start <= w.End {
+		return minuteOfDay >= w.Start && minuteOfDay < w.End
+	}
+	// Window spans midnight (e.g. 22:00-02:00)
+	return minuteOfDay >= w.Start || minuteOfDay < w.End
 }
 
 // LoadConfig loads configuration from environment variables
@@ -83,12 +221,17 @@ func LoadConfig() (*Config, error) {
 		SourceReplaceFrom: getEnvWithDefault("SOURCE_REPLACE_FROM", ""),
 		SourceReplaceTo:   getEnvWithDefault("SOURCE_REPLACE_TO", ""),
 		DestRootDir:       getEnvWithDefault("DEST_ROOT_DIR", ""),
-		TransferMethod:    strings.ToLower(getEnvWithDefault("TRANSFER_METHOD", "")), // rsync, scp, or empty for auto-detection
+		TransferMethod:    strings.ToLower(getEnvWithDefault("TRANSFER_METHOD", "")), // rsync, scp, local, or empty for auto-detection
+		TransferDirection: strings.ToLower(getEnvWithDefault("TRANSFER_DIRECTION", "push")),
+		StateDir:          getEnvWithDefault("STATE_DIR", ""),
 		SSH: SSHConfig{
-			User:     getEnvWithDefault("SSH_USER", ""),
-			Password: getEnvWithDefault("SSH_PASSWORD", ""),
-			Port:     getEnvWithDefault("SSH_PORT", "22"),
-			KeyPath:  getEnvWithDefault("SSH_KEY_PATH", ""), // Keep for future use
+			User:              getEnvWithDefault("SSH_USER", ""),
+			Password:          getEnvWithDefault("SSH_PASSWORD", ""),
+			Port:              getEnvWithDefault("SSH_PORT", "22"),
+			KeyPath:           getEnvWithDefault("SSH_KEY_PATH", ""), // Keep for future use
+			KeepaliveInterval: time.Duration(parseIntEnv("SSH_KEEPALIVE_INTERVAL", 30)) * time.Second,
+			ReconnectAttempts: int(parseIntEnv("SSH_RECONNECT_ATTEMPTS", 5)),
+			PoolSize:          int(parseIntEnv("SSH_POOL_SIZE", 2)),
 		},
 		DryRun:   parseBoolEnv("DRY_RUN", false),
 		LogLevel: getEnvWithDefault("LOG_LEVEL", "INFO"),
@@ -120,8 +263,73 @@ func LoadConfig() (*Config, error) {
 
 	// Parse transfer configuration
 	config.Transfer = TransferConfig{
-		EnableCompression: parseBoolEnv("ENABLE_COMPRESSION", true),
-		ResumeTransfers:   parseBoolEnv("RESUME_TRANSFERS", true),
+		EnableCompression:   parseBoolEnv("ENABLE_COMPRESSION", true),
+		ResumeTransfers:     parseBoolEnv("RESUME_TRANSFERS", true),
+		VerifyChecksums:     parseBoolEnv("VERIFY_CHECKSUMS", false),
+		ChecksumAlgorithm:   strings.ToLower(getEnvWithDefault("CHECKSUM_ALGORITHM", "sha256")),
+		ProgressLogInterval: time.Duration(parseIntEnv("PROGRESS_LOG_INTERVAL", 30)) * time.Second,
+		SpacePolicy:         strings.ToLower(getEnvWithDefault("SPACE_POLICY", "partial")),
+		TransferPriority:    strings.ToLower(getEnvWithDefault("TRANSFER_PRIORITY", "default")),
+		LinkMode:            strings.ToLower(getEnvWithDefault("LINK_MODE", "off")),
+		LinkSourceRoot:      getEnvWithDefault("LINK_SOURCE_ROOT", ""),
+	}
+	if config.Transfer.MinFreeSpace, err = ParseSize(getEnvWithDefault("MIN_FREE_SPACE", "")); err != nil {
+		return nil, fmt.Errorf("invalid MIN_FREE_SPACE: %w", err)
+	}
+
+	// Parse metadata sync configuration
+	config.Metadata = MetadataConfig{
+		ProgressMinDelta: time.Duration(parseIntEnv("PROGRESS_MIN_DELTA", 30)) * time.Second,
+		SyncHomeUsers:    parseBoolEnv("SYNC_HOME_USERS", false),
+		ForceRefresh:     parseBoolEnv("FORCE_METADATA_REFRESH", true),
+		SyncMarkers:      parseBoolEnv("SYNC_MARKERS", false),
+	}
+	if config.Metadata.UserMap, err = ParseUserMap(getEnvWithDefault("USER_MAP", "")); err != nil {
+		return nil, fmt.Errorf("invalid USER_MAP: %w", err)
+	}
+	if config.Metadata.LockFields, err = ParseLockFields(getEnvWithDefault("LOCK_FIELDS", "all")); err != nil {
+		return nil, fmt.Errorf("invalid LOCK_FIELDS: %w", err)
+	}
+	config.Metadata.Playlists = ParsePlaylistNames(getEnvWithDefault("SYNC_PLAYLISTS", ""))
+	config.Metadata.Policies = DefaultSyncPolicies()
+	for group, defaultPolicy := range config.Metadata.Policies {
+		envName := "SYNC_POLICY_" + strings.ToUpper(group)
+		if config.Metadata.Policies[group], err = ParseSyncPolicy(getEnvWithDefault(envName, ""), defaultPolicy); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", envName, err)
+		}
+	}
+
+	// Parse bandwidth configuration
+	if config.Bandwidth.Limit, err = ParseBandwidth(getEnvWithDefault("BANDWIDTH_LIMIT", "")); err != nil {
+		return nil, fmt.Errorf("invalid BANDWIDTH_LIMIT: %w", err)
+	}
+	if config.Destination.BandwidthLimit, err = ParseBandwidth(getEnvWithDefault("DEST_BANDWIDTH_LIMIT", "")); err != nil {
+		return nil, fmt.Errorf("invalid DEST_BANDWIDTH_LIMIT: %w", err)
+	}
+	if config.Bandwidth.Schedule, err = ParseBandwidthSchedule(getEnvWithDefault("BANDWIDTH_SCHEDULE", "")); err != nil {
+		return nil, fmt.Errorf("invalid BANDWIDTH_SCHEDULE: %w", err)
+	}
+
+	// The SSH target may differ from the Plex host (e.g. Plex behind a reverse proxy, files on a storage box)
+	// In pull mode SSH reaches the source host instead, since the destination files are local
+	defaultSSHHost := config.Destination.Host
+	if config.TransferDirection == "pull" {
+		defaultSSHHost = config.Source.Host
+	}
+	config.SSH.Host = getEnvWithDefault("SSH_HOST", defaultSSHHost)
+
+	// Parse SSH jump host chain; each hop may have its own credentials (SSH_JUMP_<n>_PASSWORD / SSH_JUMP_<n>_KEY_PATH),
+	// otherwise it uses the destination SSH key, or the SSH agent. The destination password is never sent to a hop.
+	if config.SSH.JumpHosts, err = ParseJumpHosts(getEnvWithDefault("SSH_JUMP_HOSTS", ""), config.SSH.User); err != nil {
+		return nil, fmt.Errorf("invalid SSH_JUMP_HOSTS: %w", err)
+	}
+	for i := range config.SSH.JumpHosts {
+		hop := &config.SSH.JumpHosts[i]
+		hop.Password = getEnvWithDefault(fmt.Sprintf("SSH_JUMP_%d_PASSWORD", i+1), "")
+		hop.KeyPath = getEnvWithDefault(fmt.Sprintf("SSH_JUMP_%d_KEY_PATH", i+1), "")
+		if hop.Password == "" && hop.KeyPath == "" {
+			hop.KeyPath = config.SSH.KeyPath
+		}
 	}
 
 	// Validate required fields
@@ -167,6 +375,14 @@ func (c *Config) Validate() error {
 	if sshConfigured && c.DestRootDir == "" {
 		return fmt.Errorf("DEST_ROOT_DIR is required when SSH is configured for file transfer")
 	}
+	if sshConfigured && c.SSH.Host == "" {
+		return fmt.Errorf("SSH_HOST (or DEST_PLEX_HOST) is required when SSH is configured for file transfer")
+	}
+
+	// The local method writes straight into the mounted destination, so it needs DEST_ROOT_DIR too
+	if c.TransferMethod == "local" && c.DestRootDir == "" {
+		return fmt.Errorf("DEST_ROOT_DIR is required when TRANSFER_METHOD is local")
+	}
 
 	// Validate log level
 	validLogLevels := []string{"DEBUG", "INFO", "WARN", "ERROR"}
@@ -195,6 +411,85 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("MAX_CONCURRENT_TRANSFERS must be at least 1")
 	}
 
+	// Validate SSH connection settings (only used when SSH is configured)
+	if sshConfigured {
+		if c.SSH.PoolSize < 1 {
+			return fmt.Errorf("SSH_POOL_SIZE must be at least 1")
+		}
+		if c.SSH.ReconnectAttempts < 1 {
+			return fmt.Errorf("SSH_RECONNECT_ATTEMPTS must be at least 1")
+		}
+	}
+
+	// Validate metadata sync settings
+	if c.Metadata.ProgressMinDelta < 0 {
+		return fmt.Errorf("PROGRESS_MIN_DELTA must not be negative")
+	}
+	for group, policy := range c.Metadata.Policies {
+		envName := "SYNC_POLICY_" + strings.ToUpper(group)
+		switch policy.Direction {
+		case DirectionSourceToDest, DirectionDestToSource, DirectionBidirectional, DirectionOff:
+		default:
+			return fmt.Errorf("invalid %s direction: %s (must be one of: source-to-dest, dest-to-source, bidirectional, off)", envName, policy.Direction)
+		}
+		switch policy.Conflict {
+		case ConflictNewest, ConflictSource:
+		case ConflictUnion:
+			if group != FieldGroupLabels && group != FieldGroupCollections && group != FieldGroupCredits {
+				return fmt.Errorf("invalid %s conflict rule: union only applies to labels, collections and credits", envName)
+			}
+		default:
+			return fmt.Errorf("invalid %s conflict rule: %s (must be one of: newest, source, union)", envName, policy.Conflict)
+		}
+	}
+
+	// Validate capacity planning settings
+	if c.Transfer.SpacePolicy != "" && c.Transfer.SpacePolicy != "partial" && c.Transfer.SpacePolicy != "abort" {
+		return fmt.Errorf("invalid SPACE_POLICY: %s (must be one of: partial, abort)", c.Transfer.SpacePolicy)
+	}
+	switch c.Transfer.TransferPriority {
+	case "", "default", "smallest", "largest", "newest", "oldest":
+	default:
+		return fmt.Errorf("invalid TRANSFER_PRIORITY: %s (must be one of: default, smallest, largest, newest, oldest)", c.Transfer.TransferPriority)
+	}
+
+	// Validate transfer direction; pulling fetches over SSH into a local DEST_ROOT_DIR
+	switch c.TransferDirection {
+	case "", "push":
+	case "pull":
+		if c.TransferMethod == "local" {
+			return fmt.Errorf("TRANSFER_METHOD local cannot be combined with TRANSFER_DIRECTION pull")
+		}
+		if c.Transfer.LinkMode != "" && c.Transfer.LinkMode != "off" {
+			return fmt.Errorf("LINK_MODE is not supported with TRANSFER_DIRECTION pull")
+		}
+		if c.DestRootDir == "" {
+			return fmt.Errorf("DEST_ROOT_DIR is required when TRANSFER_DIRECTION is pull")
+		}
+	default:
+		return fmt.Errorf("invalid TRANSFER_DIRECTION: %s (must be one of: push, pull)", c.TransferDirection)
+	}
+
+	// Validate link mode; linking on a remote destination needs to know where the source files live there
+	switch c.Transfer.LinkMode {
+	case "", "off":
+	case "hardlink", "reflink", "auto":
+		if c.TransferMethod != "local" && c.Transfer.LinkSourceRoot == "" {
+			return fmt.Errorf("LINK_SOURCE_ROOT is required when LINK_MODE is %s and TRANSFER_METHOD is not local", c.Transfer.LinkMode)
+		}
+	default:
+		return fmt.Errorf("invalid LINK_MODE: %s (must be one of: off, hardlink, reflink, auto)", c.Transfer.LinkMode)
+	}
+
+	// Validate checksum algorithm (only relevant when verification is enabled)
+	if c.Transfer.VerifyChecksums {
+		switch c.Transfer.ChecksumAlgorithm {
+		case "sha256", "md5", "xxh64":
+		default:
+			return fmt.Errorf("invalid CHECKSUM_ALGORITHM: %s (must be one of: sha256, md5, xxh64)", c.Transfer.ChecksumAlgorithm)
+		}
+	}
+
 	return nil
 }
 
@@ -208,6 +503,269 @@ func (c *Config) GetDestinationURL() string {
 	return fmt.Sprintf("%s://%s:%s", c.Destination.Protocol, c.Destination.Host, c.Destination.Port)
 }
 
+// BandwidthAt returns the effective transfer cap in bytes per second (0 = unlimited) at the given time,
+// and whether transfers are allowed at all. The first matching schedule window replaces the global cap;
+// the destination's own cap always applies on top.
+func (c *Config) BandwidthAt(t time.Time) (int64, bool) {
+	limit := c.Bandwidth.Limit
+
+	minuteOfDay := t.Hour()*60 + t.Minute()
+	for _, window := range c.Bandwidth.Schedule {
+		if window.Contains(minuteOfDay) {
+			if window.Off {
+				return 0, false
+			}
+			limit = window.Limit
+			break
+		}
+	}
+
+	if destLimit := c.Destination.BandwidthLimit; destLimit > 0 && (limit == 0 || destLimit < limit) {
+		limit = destLimit
+	}
+
+	return limit, true
+}
+
+// ParseBandwidth parses a rate such as "2MB", "500K" or "1.5G" (per second, binary units) into bytes
+// per second. Plain numbers are bytes per second; empty, "0" and "unlimited" mean no limit.
+func ParseBandwidth(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimSuffix(value, "/S")
+	if value == "UNLIMITED" {
+		return 0, nil
+	}
+	return ParseSize(value)
+}
+
+// ParseSize parses a size such as "10GB", "500M" or "1.5T" (binary units) into bytes.
+// Plain numbers are bytes; an empty value is zero.
+func ParseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+
+	multipliers := []struct {
+		suffix string
+		factor float64
+	}{
+		{"TB", 1 << 40}, {"T", 1 << 40},
+		{"GB", 1 << 30}, {"G", 1 << 30},
+		{"MB", 1 << 20}, {"M", 1 << 20},
+		{"KB", 1 << 10}, {"K", 1 << 10},
+		{"B", 1},
+	}
+
+	factor := 1.0
+	for _, m := range multipliers {
+		if strings.HasSuffix(value, m.suffix) {
+			factor = m.factor
+			value = strings.TrimSpace(strings.TrimSuffix(value, m.suffix))
+			break
+		}
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid size value %q", value)
+	}
+
+	return int64(amount * factor), nil
+}
+
+// ParseBandwidthSchedule parses comma-separated windows such as
+// "01:00-07:00=unlimited,18:00-23:00=off,07:00-18:00=2MB"
+func ParseBandwidthSchedule(value string) ([]BandwidthWindow, error) {
+	var windows []BandwidthWindow
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		span, rate, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("window %q must have the form HH:MM-HH:MM=rate", entry)
+		}
+
+		startStr, endStr, found := strings.Cut(span, "-")
+		if !found {
+			return nil, fmt.Errorf("window %q must have the form HH:MM-HH:MM=rate", entry)
+		}
+
+		start, err := parseClock(startStr)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(endStr)
+		if err != nil {
+			return nil, err
+		}
+
+		window := BandwidthWindow{Start: start, End: end}
+		if strings.EqualFold(strings.TrimSpace(rate), "off") {
+			window.Off = true
+		} else if window.Limit, err = ParseBandwidth(rate); err != nil {
+			return nil, err
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+// ParseJumpHosts parses a comma-separated jump host chain of [user@]host[:port] entries, e.g.
+// "admin@bastion.example.com:2222,relay.lan". Hops without a user get defaultUser; the port defaults to 22.
+func ParseJumpHosts(value, defaultUser string) ([]JumpHost, error) {
+	var hops []JumpHost
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		hop := JumpHost{User: defaultUser, Port: "22"}
+		hostPort := entry
+		if user, rest, found := strings.Cut(entry, "@"); found {
+			hop.User = user
+			hostPort = rest
+		}
+
+		hop.Host = hostPort
+		if host, port, err := net.SplitHostPort(hostPort); err == nil {
+			hop.Host = host
+			hop.Port = port
+		}
+
+		if hop.Host == "" || hop.User == "" {
+			return nil, fmt.Errorf("jump host %q must have the form user@host[:port]", entry)
+		}
+		if _, err := strconv.Atoi(hop.Port); err != nil {
+			return nil, fmt.Errorf("jump host %q has an invalid port", entry)
+		}
+
+		hops = append(hops, hop)
+	}
+
+	return hops, nil
+}
+
+// ParseUserMap parses a comma-separated list of source=destination Plex Home user names, e.g. "alice=Alicia,kids=Children".
+// Names are matched case-insensitively, so both sides are stored lowercased.
+func ParseUserMap(value string) (map[string]string, error) {
+	userMap := make(map[string]string)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		source, dest, found := strings.Cut(entry, "=")
+		source = strings.ToLower(strings.TrimSpace(source))
+		dest = strings.ToLower(strings.TrimSpace(dest))
+		if !found || source == "" || dest == "" {
+			return nil, fmt.Errorf("user mapping %q must have the form source=destination", entry)
+		}
+		if _, exists := userMap[source]; exists {
+			return nil, fmt.Errorf("user %q is mapped more than once", source)
+		}
+
+		userMap[source] = dest
+	}
+
+	return userMap, nil
+}
+
+// ParseLockFields parses a comma-separated list of fields to lock, e.g. "title,summary,label".
+// "all" selects every lockable field and "none" (or an empty value) selects none.
+func ParseLockFields(value string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return fields, nil
+	case "all":
+		for _, field := range LockableFields {
+			fields[field] = true
+		}
+		return fields, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		found := false
+		for _, field := range LockableFields {
+			if strings.EqualFold(entry, field) {
+				fields[field] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q (valid: %s, all, none)", entry, strings.Join(LockableFields, ", "))
+		}
+	}
+
+	return fields, nil
+}
+
+// ParsePlaylistNames parses a comma-separated list of playlist titles, e.g. "Movie Night,Kids".
+// Titles are matched case-insensitively, so they are stored lowercased.
+func ParsePlaylistNames(value string) map[string]bool {
+	names := make(map[string]bool)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" {
+			names[entry] = true
+		}
+	}
+
+	return names
+}
+
+// ParseSyncPolicy parses a field group policy of the form "direction[:conflict]", e.g. "bidirectional:union".
+// An empty value yields defaultPolicy; a missing conflict rule keeps the default rule.
+func ParseSyncPolicy(value string, defaultPolicy SyncPolicy) (SyncPolicy, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return defaultPolicy, nil
+	}
+
+	policy := defaultPolicy
+	direction, conflict, hasConflict := strings.Cut(value, ":")
+	policy.Direction = strings.TrimSpace(direction)
+	if hasConflict {
+		policy.Conflict = strings.TrimSpace(conflict)
+	}
+	if policy.Direction == "" || policy.Conflict == "" {
+		return SyncPolicy{}, fmt.Errorf("policy %q must have the form direction[:conflict]", value)
+	}
+
+	return policy, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight ("24:00" is accepted as end of day)
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		if strings.TrimSpace(value) == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
 // Helper functions for parsing environment variables
 
 func getEnvWithDefault(key, defaultValue string) string {
@@ -119,6 +119,245 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "invalid checksum algorithm",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel: "sync",
+				LogLevel:  "INFO",
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+				Transfer: TransferConfig{
+					VerifyChecksums:   true,
+					ChecksumAlgorithm: "crc32",
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "xxh64 checksum algorithm",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel: "sync",
+				LogLevel:  "INFO",
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+				Transfer: TransferConfig{
+					VerifyChecksums:   true,
+					ChecksumAlgorithm: "xxh64",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "local transfer without destination root",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel:      "sync",
+				LogLevel:       "INFO",
+				TransferMethod: "local",
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "remote link mode without link source root",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel: "sync",
+				LogLevel:  "INFO",
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+				Transfer: TransferConfig{
+					LinkMode: "hardlink",
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "ssh pool size below one",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SSH: SSHConfig{
+					Host:              "dest.local",
+					User:              "user",
+					Password:          "password",
+					ReconnectAttempts: 5,
+					PoolSize:          0,
+				},
+				SyncLabel:   "sync",
+				LogLevel:    "INFO",
+				DestRootDir: "/mnt/data",
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "pull with local transfer method",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel:         "sync",
+				LogLevel:          "INFO",
+				DestRootDir:       "/mnt/data",
+				TransferMethod:    "local",
+				TransferDirection: "pull",
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "negative progress min delta",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel: "sync",
+				LogLevel:  "INFO",
+				Metadata: MetadataConfig{
+					ProgressMinDelta: -time.Second,
+				},
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "union conflict rule on text fields",
+			config: Config{
+				Source: PlexServerConfig{
+					Host:     "source.local",
+					Port:     "32400",
+					Token:    "source-token",
+					Protocol: "http",
+				},
+				Destination: PlexServerConfig{
+					Host:     "dest.local",
+					Port:     "32400",
+					Token:    "dest-token",
+					Protocol: "http",
+				},
+				SyncLabel: "sync",
+				LogLevel:  "INFO",
+				Metadata: MetadataConfig{
+					Policies: map[string]SyncPolicy{
+						FieldGroupText: {Direction: DirectionBidirectional, Conflict: ConflictUnion},
+					},
+				},
+				Performance: PerformanceConfig{
+					WorkerPoolSize:         4,
+					PlexAPIRateLimit:       10.0,
+					TransferBufferSize:     65536,
+					MaxConcurrentTransfers: 3,
+				},
+			},
+			wantError: true,
+		},
 		{
 			name: "missing source host",
 			config: Config{
@@ -166,3 +405,257 @@ func TestConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBandwidth(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      int64
+		wantError bool
+	}{
+		{input: "", want: 0},
+		{input: "unlimited", want: 0},
+		{input: "2MB", want: 2 * 1024 * 1024},
+		{input: "500k", want: 500 * 1024},
+		{input: "1.5G/s", want: 1536 * 1024 * 1024},
+		{input: "4096", want: 4096},
+		{input: "fast", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBandwidth(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseBandwidth(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBandwidth(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBandwidthAt(t *testing.T) {
+	schedule, err := ParseBandwidthSchedule("01:00-07:00=unlimited,18:00-23:00=off,23:00-01:00=1MB")
+	if err != nil {
+		t.Fatalf("ParseBandwidthSchedule() failed: %v", err)
+	}
+
+	cfg := &Config{
+		Bandwidth: BandwidthConfig{
+			Limit:    2 * 1024 * 1024,
+			Schedule: schedule,
+		},
+	}
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		time        time.Time
+		wantLimit   int64
+		wantAllowed bool
+	}{
+		{name: "full speed window", time: at(3, 0), wantLimit: 0, wantAllowed: true},
+		{name: "default limit", time: at(12, 0), wantLimit: 2 * 1024 * 1024, wantAllowed: true},
+		{name: "blocked window", time: at(18, 30), wantLimit: 0, wantAllowed: false},
+		{name: "window spanning midnight", time: at(0, 15), wantLimit: 1024 * 1024, wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, allowed := cfg.BandwidthAt(tt.time)
+			if limit != tt.wantLimit || allowed != tt.wantAllowed {
+				t.Errorf("BandwidthAt() = (%d, %v), want (%d, %v)", limit, allowed, tt.wantLimit, tt.wantAllowed)
+			}
+		})
+	}
+
+	// The destination cap applies even inside a full speed window
+	cfg.Destination.BandwidthLimit = 512 * 1024
+	if limit, _ := cfg.BandwidthAt(at(3, 0)); limit != 512*1024 {
+		t.Errorf("Expected destination cap 524288, got %d", limit)
+	}
+}
+
+func TestParseJumpHosts(t *testing.T) {
+	hops, err := ParseJumpHosts("admin@bastion.example.com:2222, relay.lan", "syncarr")
+	if err != nil {
+		t.Fatalf("ParseJumpHosts() failed: %v", err)
+	}
+
+	want := []JumpHost{
+		{User: "admin", Host: "bastion.example.com", Port: "2222"},
+		{User: "syncarr", Host: "relay.lan", Port: "22"},
+	}
+	if len(hops) != len(want) {
+		t.Fatalf("ParseJumpHosts() returned %d hops, want %d", len(hops), len(want))
+	}
+	for i := range want {
+		if hops[i] != want[i] {
+			t.Errorf("hop %d = %+v, want %+v", i, hops[i], want[i])
+		}
+	}
+
+	if _, err := ParseJumpHosts("admin@bastion:ssh", "syncarr"); err == nil {
+		t.Error("ParseJumpHosts() accepted a non-numeric port")
+	}
+}
+
+func TestJumpHostCredentials(t *testing.T) {
+	testEnvVars := map[string]string{
+		"SOURCE_PLEX_HOST":    "test-source.local",
+		"SOURCE_PLEX_TOKEN":   "test-source-token",
+		"DEST_PLEX_HOST":      "test-dest.local",
+		"DEST_PLEX_TOKEN":     "test-dest-token",
+		"SYNC_LABEL":          "test-sync",
+		"SSH_USER":            "testuser",
+		"SSH_PASSWORD":        "dest-password",
+		"SSH_KEY_PATH":        "/test/keys/id_rsa",
+		"DEST_ROOT_DIR":       "/test/dest",
+		"SSH_JUMP_HOSTS":      "bastion.lan,relay.lan",
+		"SSH_JUMP_2_PASSWORD": "relay-password",
+	}
+	for key, value := range testEnvVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	// A hop without credentials gets the destination key but never the destination password
+	bastion := cfg.SSH.JumpHosts[0]
+	if bastion.Password != "" || bastion.KeyPath != "/test/keys/id_rsa" {
+		t.Errorf("bastion credentials = (%q, %q), want destination key only", bastion.Password, bastion.KeyPath)
+	}
+	relay := cfg.SSH.JumpHosts[1]
+	if relay.Password != "relay-password" || relay.KeyPath != "" {
+		t.Errorf("relay credentials = (%q, %q), want its own password only", relay.Password, relay.KeyPath)
+	}
+}
+
+func TestParseUserMap(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      map[string]string
+		wantError bool
+	}{
+		{input: "", want: map[string]string{}},
+		{input: "alice=Alicia", want: map[string]string{"alice": "alicia"}},
+		{input: " Alice = Alicia , kids=Children ", want: map[string]string{"alice": "alicia", "kids": "children"}},
+		{input: "alice", wantError: true},
+		{input: "alice=", wantError: true},
+		{input: "=alicia", wantError: true},
+		{input: "alice=one,ALICE=two", wantError: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUserMap(tt.input)
+		if (err != nil) != tt.wantError {
+			t.Errorf("ParseUserMap(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+			continue
+		}
+		if tt.wantError {
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseUserMap(%q) = %v, want %v", tt.input, got, tt.want)
+			continue
+		}
+		for source, dest := range tt.want {
+			if got[source] != dest {
+				t.Errorf("ParseUserMap(%q)[%q] = %q, want %q", tt.input, source, got[source], dest)
+			}
+		}
+	}
+}
+
+func TestParseLockFields(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      []string
+		wantError bool
+	}{
+		{input: "", want: nil},
+		{input: "none", want: nil},
+		{input: "ALL", want: LockableFields},
+		{input: "title, summary", want: []string{"title", "summary"}},
+		{input: "titlesort,Label", want: []string{"titleSort", "label"}},
+		{input: "title,rating", wantError: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLockFields(tt.input)
+		if (err != nil) != tt.wantError {
+			t.Errorf("ParseLockFields(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+			continue
+		}
+		if tt.wantError {
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseLockFields(%q) = %v, want %v", tt.input, got, tt.want)
+			continue
+		}
+		for _, field := range tt.want {
+			if !got[field] {
+				t.Errorf("ParseLockFields(%q) is missing %q", tt.input, field)
+			}
+		}
+	}
+}
+
+func TestParsePlaylistNames(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "", want: nil},
+		{input: "Movie Night", want: []string{"movie night"}},
+		{input: " Movie Night , Kids,, ", want: []string{"movie night", "kids"}},
+	}
+
+	for _, tt := range tests {
+		got := ParsePlaylistNames(tt.input)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParsePlaylistNames(%q) = %v, want %v", tt.input, got, tt.want)
+			continue
+		}
+		for _, name := range tt.want {
+			if !got[name] {
+				t.Errorf("ParsePlaylistNames(%q) is missing %q", tt.input, name)
+			}
+		}
+	}
+}
+
+func TestParseSyncPolicy(t *testing.T) {
+	defaultPolicy := SyncPolicy{Direction: DirectionSourceToDest, Conflict: ConflictSource}
+	tests := []struct {
+		input     string
+		want      SyncPolicy
+		wantError bool
+	}{
+		{input: "", want: defaultPolicy},
+		{input: "bidirectional", want: SyncPolicy{Direction: DirectionBidirectional, Conflict: ConflictSource}},
+		{input: "Bidirectional:Union", want: SyncPolicy{Direction: DirectionBidirectional, Conflict: ConflictUnion}},
+		{input: "off", want: SyncPolicy{Direction: DirectionOff, Conflict: ConflictSource}},
+		{input: ":newest", wantError: true},
+		{input: "bidirectional:", wantError: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSyncPolicy(tt.input, defaultPolicy)
+		if (err != nil) != tt.wantError {
+			t.Errorf("ParseSyncPolicy(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+			continue
+		}
+		if !tt.wantError && got != tt.want {
+			t.Errorf("ParseSyncPolicy(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
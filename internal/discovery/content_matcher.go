@@ -6,12 +6,14 @@ import (
 
 	"github.com/nullable-eth/syncarr/internal/logger"
 	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
 )
 
 // ContentMatcher handles Phase 6: Content Matching
 type ContentMatcher struct {
 	sourceClient *plex.Client
 	destClient   *plex.Client
+	destIndex    *DestinationIndex
 	logger       *logger.Logger
 }
 
@@ -22,114 +24,174 @@ type ItemMatch struct {
 	Filename   string
 }
 
-// NewContentMatcher creates a new content matcher
-func NewContentMatcher(sourceClient, destClient *plex.Client, log *logger.Logger) *ContentMatcher {
+// NewContentMatcher creates a new content matcher backed by a persistent destination index
+func NewContentMatcher(sourceClient, destClient *plex.Client, store *state.Store, log *logger.Logger) *ContentMatcher {
 	return &ContentMatcher{
 		sourceClient: sourceClient,
 		destClient:   destClient,
+		destIndex:    NewDestinationIndex(destClient, store, log),
 		logger:       log,
 	}
 }
 
-// MatchItemsByFilename implements Phase 6: Content Matching by filename with full metadata
+// MatchItemsByFilename implements Phase 6: Content Matching by filename (falling back to GUID).
+// Only destination items that matched a source item have their full metadata loaded.
 func (cm *ContentMatcher) MatchItemsByFilename(sourceItems []*EnhancedMediaItem) ([]ItemMatch, error) {
 	cm.logger.Info("Phase 6: START - Content Matching")
 
-	// Get all items from destination server and load their full metadata
-	destLibraries, err := cm.destClient.GetLibraries()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get destination libraries: %w", err)
+	// Only libraries that can hold the source items need to be indexed
+	libraryTypes := make(map[string]bool)
+	for _, sourceEnhanced := range sourceItems {
+		switch sourceEnhanced.ItemType {
+		case "movie":
+			libraryTypes["movie"] = true
+		case "show", "season", "episode":
+			libraryTypes["show"] = true
+		}
 	}
 
-	var allDestItems []*EnhancedMediaItem
-	for _, library := range destLibraries {
-		cm.logger.WithFields(map[string]interface{}{
-			"library_id":    library.Key,
-			"library_title": library.Title,
-		}).Debug("Retrieving items from destination library with full metadata")
+	if err := cm.destIndex.Refresh(libraryTypes); err != nil {
+		return nil, fmt.Errorf("failed to refresh destination index: %w", err)
+	}
+
+	// Match source items to destination items
+	var matches []ItemMatch
+	for _, sourceEnhanced := range sourceItems {
+		entry, matchKey := cm.findDestinationEntry(sourceEnhanced)
+		if entry == nil {
+			continue
+		}
 
-		// Get library content using Key
-		items, err := cm.destClient.GetLibraryContent(library.Key)
+		destEnhanced, err := cm.loadDestinationFullMetadata(entry)
 		if err != nil {
-			cm.logger.WithError(err).WithField("library_id", library.Key).Warn("Failed to get content from destination library")
+			cm.logger.WithError(err).WithFields(map[string]interface{}{
+				"dest_key":   entry.RatingKey,
+				"dest_title": entry.Title,
+			}).Debug("Failed to load full metadata for matched destination item")
+			continue
+		}
+
+		matches = append(matches, ItemMatch{
+			SourceItem: sourceEnhanced,
+			DestItem:   destEnhanced,
+			Filename:   matchKey,
+		})
+
+		cm.logger.WithFields(map[string]interface{}{
+			"match_key":   matchKey,
+			"source_item": cm.getEnhancedItemTitle(sourceEnhanced),
+			"dest_item":   cm.getEnhancedItemTitle(destEnhanced),
+		}).Debug("Found match with full metadata")
+	}
+
+	cm.logger.WithFields(map[string]interface{}{
+		"source_items": len(sourceItems),
+		"matches":      len(matches),
+	}).Info("Enhanced content matching with full metadata complete")
+
+	return matches, nil
+}
+
+// ExpandSeasons replaces labeled seasons with their episodes (with full metadata). Seasons are not indexed on
+// the destination, and the watched state and metadata that need syncing live on the episodes.
+func (cm *ContentMatcher) ExpandSeasons(sourceItems []*EnhancedMediaItem) []*EnhancedMediaItem {
+	expanded := make([]*EnhancedMediaItem, 0, len(sourceItems))
+	for _, sourceEnhanced := range sourceItems {
+		season, ok := sourceEnhanced.Item.(plex.Season)
+		if !ok {
+			expanded = append(expanded, sourceEnhanced)
 			continue
 		}
 
-		// Load full metadata for each destination item
-		for i, item := range items {
-			cm.logger.WithFields(map[string]interface{}{
-				"progress": fmt.Sprintf("%d/%d", i+1, len(items)),
-				"library":  library.Title,
-			}).Debug("Loading full metadata for destination item")
+		episodes, err := cm.sourceClient.GetSeasonEpisodes(season.RatingKey.String())
+		if err != nil {
+			cm.logger.WithError(err).WithFields(map[string]interface{}{
+				"show":   season.ParentTitle,
+				"season": season.Index,
+			}).Warn("Failed to get episodes of labeled season for matching")
+			continue
+		}
 
-			enhancedItem, err := cm.loadDestinationFullMetadata(item, library.Key, library.Type)
+		for _, episode := range episodes {
+			fullEpisode, err := cm.sourceClient.GetEpisodeDetails(episode.RatingKey.String())
 			if err != nil {
-				cm.logger.WithError(err).WithField("item", fmt.Sprintf("%T", item)).Debug("Failed to load full metadata for destination item")
+				cm.logger.WithError(err).WithField("episode", episode.Title).Warn("Failed to load full metadata for season episode")
 				continue
 			}
-
-			if enhancedItem != nil {
-				allDestItems = append(allDestItems, enhancedItem)
-			}
+			expanded = append(expanded, &EnhancedMediaItem{
+				Item:      *fullEpisode,
+				LibraryID: sourceEnhanced.LibraryID,
+				ItemType:  "episode",
+			})
 		}
 	}
+	return expanded
+}
 
-	// Build filename index for destination items with enhanced metadata
-	destFileIndex := make(map[string]*EnhancedMediaItem)
-	for _, enhancedItem := range allDestItems {
-		// Extract file paths from the enhanced item
-		filePaths := cm.extractEnhancedFilePaths(enhancedItem)
-		for _, filePath := range filePaths {
-			filename := filepath.Base(filePath)
-			if filename != "" {
-				destFileIndex[filename] = enhancedItem
-			}
+// MatchPlaylistItems maps source playlist entries (by rating key) to destination rating keys, matching them by
+// file name and then GUID like library items. Entries without a copy on the destination are left out.
+func (cm *ContentMatcher) MatchPlaylistItems(items []plex.PlaylistItem) map[string]string {
+	matched := make(map[string]string)
+	for _, item := range items {
+		var enhanced *EnhancedMediaItem
+		switch item.Type {
+		case "movie":
+			enhanced = &EnhancedMediaItem{Item: plex.Movie{Guid: item.Guid, Media: item.Media}, ItemType: "movie"}
+		case "episode":
+			enhanced = &EnhancedMediaItem{Item: plex.Episode{Guid: item.Guid, Media: item.Media}, ItemType: "episode"}
+		default:
+			continue
+		}
+
+		if entry, _ := cm.findDestinationEntry(enhanced); entry != nil {
+			matched[item.RatingKey.String()] = entry.RatingKey
 		}
 	}
+	return matched
+}
 
-	cm.logger.WithFields(map[string]interface{}{
-		"dest_items":    len(allDestItems),
-		"indexed_files": len(destFileIndex),
-	}).Info("Built enhanced destination file index with full metadata")
+// findDestinationEntry looks up the destination index entry for a source item, first by file name
+// and then by GUID (shows carry no files of their own, and renamed files still share GUIDs)
+func (cm *ContentMatcher) findDestinationEntry(sourceEnhanced *EnhancedMediaItem) (*IndexEntry, string) {
+	for _, sourceFilePath := range cm.extractEnhancedFilePaths(sourceEnhanced) {
+		sourceFilename := filepath.Base(sourceFilePath)
+		if sourceFilename == "" {
+			continue
+		}
+		if entry, exists := cm.destIndex.LookupFilename(sourceFilename); exists {
+			return entry, sourceFilename
+		}
+	}
 
-	// Match source items to destination items
-	var matches []ItemMatch
-	for _, sourceEnhanced := range sourceItems {
-		// Extract file paths from source enhanced item
-		sourceFilePaths := cm.extractEnhancedFilePaths(sourceEnhanced)
+	for _, guid := range cm.extractGuids(sourceEnhanced.Item) {
+		if entry, exists := cm.destIndex.LookupGUID(sourceEnhanced.ItemType, guid); exists {
+			return entry, guid
+		}
+	}
 
-		for _, sourceFilePath := range sourceFilePaths {
-			sourceFilename := filepath.Base(sourceFilePath)
-			if sourceFilename == "" {
-				continue
-			}
+	return nil, ""
+}
 
-			// Look for exact filename match
-			if destEnhanced, exists := destFileIndex[sourceFilename]; exists {
-				match := ItemMatch{
-					SourceItem: sourceEnhanced,
-					DestItem:   destEnhanced,
-					Filename:   sourceFilename,
-				}
-				matches = append(matches, match)
+// extractGuids extracts all GUIDs of an item
+func (cm *ContentMatcher) extractGuids(item interface{}) []string {
+	var guids []plex.Guid
 
-				cm.logger.WithFields(map[string]interface{}{
-					"filename":    sourceFilename,
-					"source_item": cm.getEnhancedItemTitle(sourceEnhanced),
-					"dest_item":   cm.getEnhancedItemTitle(destEnhanced),
-				}).Debug("Found enhanced filename match with full metadata")
+	switch v := item.(type) {
+	case plex.Movie:
+		guids = v.Guid
+	case plex.TVShow:
+		guids = v.Guid
+	case plex.Episode:
+		guids = v.Guid
+	}
 
-				break // Only match once per source item
-			}
+	var ids []string
+	for _, guid := range guids {
+		if guid.ID != "" {
+			ids = append(ids, guid.ID)
 		}
 	}
-
-	cm.logger.WithFields(map[string]interface{}{
-		"source_items": len(sourceItems),
-		"matches":      len(matches),
-	}).Info("Enhanced content matching with full metadata complete")
-
-	return matches, nil
+	return ids
 }
 
 // extractFilePaths extracts file paths from metadata
@@ -173,6 +235,8 @@ func (cm *ContentMatcher) getItemTitle(item interface{}) string {
 		return v.Title
 	case plex.TVShow:
 		return v.Title
+	case plex.Season:
+		return fmt.Sprintf("%s - %s", v.ParentTitle, v.Title)
 	case plex.Episode:
 		return v.Title
 	default:
@@ -180,62 +244,44 @@ func (cm *ContentMatcher) getItemTitle(item interface{}) string {
 	}
 }
 
-// loadDestinationFullMetadata loads complete metadata for a destination item
-func (cm *ContentMatcher) loadDestinationFullMetadata(item interface{}, libraryID, libraryType string) (*EnhancedMediaItem, error) {
-	// Get the rating key from the basic item
-	ratingKey := cm.getRatingKey(item)
-	if ratingKey == "" {
-		return nil, fmt.Errorf("item has no rating key")
-	}
-
-	// Load full metadata based on item type
-	switch item.(type) {
-	case plex.Movie:
-		fullMovie, err := cm.destClient.GetMovieDetails(ratingKey)
+// loadDestinationFullMetadata lazily loads complete metadata for a matched destination index entry
+func (cm *ContentMatcher) loadDestinationFullMetadata(entry *IndexEntry) (*EnhancedMediaItem, error) {
+	switch entry.ItemType {
+	case "movie":
+		fullMovie, err := cm.destClient.GetMovieDetails(entry.RatingKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load full destination movie metadata: %w", err)
 		}
 		return &EnhancedMediaItem{
 			Item:      *fullMovie,
-			LibraryID: libraryID,
+			LibraryID: entry.LibraryID,
 			ItemType:  "movie",
 		}, nil
 
-	case plex.TVShow:
-		fullTVShow, err := cm.destClient.GetTVShowDetails(ratingKey)
+	case "show":
+		fullTVShow, err := cm.destClient.GetTVShowDetails(entry.RatingKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load full destination TV show metadata: %w", err)
 		}
 		return &EnhancedMediaItem{
 			Item:      *fullTVShow,
-			LibraryID: libraryID,
+			LibraryID: entry.LibraryID,
 			ItemType:  "show",
 		}, nil
 
-	case plex.Episode:
-		// For episodes, use the basic item for now
+	case "episode":
+		fullEpisode, err := cm.destClient.GetEpisodeDetails(entry.RatingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load full destination episode metadata: %w", err)
+		}
 		return &EnhancedMediaItem{
-			Item:      item,
-			LibraryID: libraryID,
+			Item:      *fullEpisode,
+			LibraryID: entry.LibraryID,
 			ItemType:  "episode",
 		}, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported destination item type: %T", item)
-	}
-}
-
-// getRatingKey safely extracts rating key from any item type
-func (cm *ContentMatcher) getRatingKey(item interface{}) string {
-	switch v := item.(type) {
-	case plex.Movie:
-		return v.RatingKey.String()
-	case plex.TVShow:
-		return v.RatingKey.String()
-	case plex.Episode:
-		return v.RatingKey.String()
-	default:
-		return ""
+		return nil, fmt.Errorf("unsupported destination item type: %s", entry.ItemType)
 	}
 }
 
@@ -0,0 +1,312 @@
+package discovery
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
+)
+
+// destinationIndexDocument is the state store document name for the destination index
+const destinationIndexDocument = "destination-index"
+
+// IndexEntry is a lightweight record of a destination item used for matching
+type IndexEntry struct {
+	RatingKey   string   `json:"ratingKey"`
+	LibraryID   string   `json:"libraryId"`
+	LibraryType string   `json:"libraryType"`
+	ItemType    string   `json:"itemType"` // "movie", "show", "episode"
+	MediaType   int      `json:"mediaType"`
+	Title       string   `json:"title"`
+	UpdatedAt   int      `json:"updatedAt"`
+	Files       []string `json:"files,omitempty"`
+	Guids       []string `json:"guids,omitempty"`
+}
+
+// indexedLibrary holds the cached entries of one destination library
+type indexedLibrary struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	// Watermarks holds the highest updatedAt seen per metadata type, used for incremental refreshes
+	Watermarks map[int]int            `json:"watermarks"`
+	Entries    map[string]*IndexEntry `json:"entries"`
+}
+
+// DestinationIndex is a persistent index of destination library items keyed by file name and GUID.
+// It is refreshed incrementally using the items' updatedAt timestamps instead of reloading everything.
+type DestinationIndex struct {
+	Libraries map[string]*indexedLibrary `json:"libraries"`
+
+	destClient *plex.Client
+	store      *state.Store
+	logger     *logger.Logger
+	byFilename map[string]*IndexEntry
+	byGUID     map[string]*IndexEntry
+}
+
+// NewDestinationIndex creates a destination index and loads any previously persisted state
+func NewDestinationIndex(destClient *plex.Client, store *state.Store, log *logger.Logger) *DestinationIndex {
+	index := &DestinationIndex{
+		Libraries:  make(map[string]*indexedLibrary),
+		destClient: destClient,
+		store:      store,
+		logger:     log,
+	}
+
+	if err := store.Load(destinationIndexDocument, index); err != nil {
+		log.WithError(err).Warn("Failed to load persisted destination index, rebuilding from scratch")
+		index.Libraries = make(map[string]*indexedLibrary)
+	}
+	if index.Libraries == nil {
+		index.Libraries = make(map[string]*indexedLibrary)
+	}
+	index.rebuildLookups()
+
+	return index
+}
+
+// mediaTypesForLibrary returns the metadata types indexed for a library type
+func mediaTypesForLibrary(libraryType string) []int {
+	switch libraryType {
+	case "movie":
+		return []int{plex.MediaTypeMovie}
+	case "show":
+		return []int{plex.MediaTypeShow, plex.MediaTypeEpisode}
+	default:
+		return nil
+	}
+}
+
+// Refresh brings the index up to date for all destination libraries of the given types.
+// Libraries of other types are skipped because they cannot contain the source items.
+func (di *DestinationIndex) Refresh(libraryTypes map[string]bool) error {
+	libraries, err := di.destClient.GetLibraries()
+	if err != nil {
+		return fmt.Errorf("failed to get destination libraries: %w", err)
+	}
+
+	present := make(map[string]bool)
+	for _, library := range libraries {
+		present[library.Key] = true
+
+		if !libraryTypes[library.Type] {
+			di.logger.WithFields(map[string]interface{}{
+				"library_id":    library.Key,
+				"library_title": library.Title,
+				"library_type":  library.Type,
+			}).Debug("Skipping destination library that cannot contain source items")
+			continue
+		}
+
+		cached, exists := di.Libraries[library.Key]
+		if !exists || cached.Type != library.Type {
+			cached = &indexedLibrary{
+				Key:        library.Key,
+				Type:       library.Type,
+				Watermarks: make(map[int]int),
+				Entries:    make(map[string]*IndexEntry),
+			}
+			di.Libraries[library.Key] = cached
+		}
+		cached.Title = library.Title
+		if cached.Watermarks == nil {
+			cached.Watermarks = make(map[int]int)
+		}
+		if cached.Entries == nil {
+			cached.Entries = make(map[string]*IndexEntry)
+		}
+
+		for _, mediaType := range mediaTypesForLibrary(library.Type) {
+			if err := di.refreshLibraryType(cached, mediaType); err != nil {
+				di.logger.WithError(err).WithFields(map[string]interface{}{
+					"library_id": library.Key,
+					"media_type": mediaType,
+				}).Warn("Failed to refresh destination index for library")
+			}
+		}
+	}
+
+	// Forget libraries that no longer exist on the destination
+	for key := range di.Libraries {
+		if !present[key] {
+			delete(di.Libraries, key)
+		}
+	}
+
+	di.rebuildLookups()
+
+	if err := di.store.Save(destinationIndexDocument, di); err != nil {
+		di.logger.WithError(err).Warn("Failed to persist destination index")
+	}
+
+	di.logger.WithFields(map[string]interface{}{
+		"libraries":     len(di.Libraries),
+		"indexed_files": len(di.byFilename),
+		"indexed_guids": len(di.byGUID),
+	}).Info("Destination index refreshed")
+
+	return nil
+}
+
+// refreshLibraryType updates one metadata type of a library, incrementally when possible
+func (di *DestinationIndex) refreshLibraryType(library *indexedLibrary, mediaType int) error {
+	watermark := library.Watermarks[mediaType]
+
+	if watermark > 0 {
+		changed, err := di.destClient.GetSectionItems(library.Key, mediaType, watermark-1)
+		if err != nil {
+			return err
+		}
+		for _, item := range changed {
+			di.upsert(library, mediaType, item)
+		}
+
+		// Deletions are not reported by the updatedAt filter, so the indexed keys are reconciled with the server's
+		keys, err := di.destClient.GetSectionItemKeys(library.Key, mediaType)
+		if err != nil {
+			return err
+		}
+		removed, missing := di.reconcileKeys(library, mediaType, keys)
+		if missing == 0 {
+			di.logger.WithFields(map[string]interface{}{
+				"library_id":    library.Key,
+				"media_type":    mediaType,
+				"changed_items": len(changed),
+				"removed_items": removed,
+			}).Debug("Incrementally refreshed destination index")
+			return nil
+		}
+
+		di.logger.WithFields(map[string]interface{}{
+			"library_id":    library.Key,
+			"media_type":    mediaType,
+			"missing_items": missing,
+		}).Debug("Destination has items the index missed, reloading library index")
+	}
+
+	items, err := di.destClient.GetSectionItems(library.Key, mediaType, 0)
+	if err != nil {
+		return err
+	}
+
+	for key, entry := range library.Entries {
+		if entry.MediaType == mediaType {
+			delete(library.Entries, key)
+		}
+	}
+	library.Watermarks[mediaType] = 0
+	for _, item := range items {
+		di.upsert(library, mediaType, item)
+	}
+
+	di.logger.WithFields(map[string]interface{}{
+		"library_id": library.Key,
+		"media_type": mediaType,
+		"item_count": len(items),
+	}).Debug("Fully reloaded destination index for library")
+
+	return nil
+}
+
+// upsert adds or replaces an index entry and advances the library watermark
+func (di *DestinationIndex) upsert(library *indexedLibrary, mediaType int, item plex.IndexItem) {
+	entry := &IndexEntry{
+		RatingKey:   item.RatingKey.String(),
+		LibraryID:   library.Key,
+		LibraryType: library.Type,
+		ItemType:    itemTypeForMediaType(mediaType),
+		MediaType:   mediaType,
+		Title:       item.Title,
+		UpdatedAt:   item.UpdatedAt,
+	}
+	if item.PlexGuid != "" {
+		entry.Guids = append(entry.Guids, item.PlexGuid)
+	}
+	for _, guid := range item.Guid {
+		if guid.ID != "" {
+			entry.Guids = append(entry.Guids, guid.ID)
+		}
+	}
+	for _, media := range item.Media {
+		for _, part := range media.Part {
+			if part.File != "" {
+				entry.Files = append(entry.Files, part.File)
+			}
+		}
+	}
+
+	library.Entries[entry.RatingKey] = entry
+	if item.UpdatedAt > library.Watermarks[mediaType] {
+		library.Watermarks[mediaType] = item.UpdatedAt
+	}
+}
+
+// reconcileKeys drops the indexed entries of one metadata type whose rating keys are no longer on the
+// server, and counts the server's keys that have no entry
+func (di *DestinationIndex) reconcileKeys(library *indexedLibrary, mediaType int, keys []string) (removed, missing int) {
+	present := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		present[key] = true
+		if _, exists := library.Entries[key]; !exists {
+			missing++
+		}
+	}
+
+	for key, entry := range library.Entries {
+		if entry.MediaType == mediaType && !present[key] {
+			delete(library.Entries, key)
+			removed++
+		}
+	}
+	return removed, missing
+}
+
+// rebuildLookups regenerates the file name and GUID lookup maps from the cached entries
+func (di *DestinationIndex) rebuildLookups() {
+	di.byFilename = make(map[string]*IndexEntry)
+	di.byGUID = make(map[string]*IndexEntry)
+
+	for _, library := range di.Libraries {
+		for _, entry := range library.Entries {
+			for _, file := range entry.Files {
+				if filename := filepath.Base(file); filename != "" {
+					di.byFilename[filename] = entry
+				}
+			}
+			for _, guid := range entry.Guids {
+				di.byGUID[entry.ItemType+"|"+guid] = entry
+			}
+		}
+	}
+}
+
+// LookupFilename finds the destination entry owning a file with the given base name
+func (di *DestinationIndex) LookupFilename(filename string) (*IndexEntry, bool) {
+	entry, exists := di.byFilename[filename]
+	return entry, exists
+}
+
+// LookupGUID finds the destination entry of the given item type carrying the given GUID
+func (di *DestinationIndex) LookupGUID(itemType, guid string) (*IndexEntry, bool) {
+	entry, exists := di.byGUID[itemType+"|"+guid]
+	return entry, exists
+}
+
+// itemTypeForMediaType converts a Plex metadata type to the enhanced item type name
+func itemTypeForMediaType(mediaType int) string {
+	switch mediaType {
+	case plex.MediaTypeMovie:
+		return "movie"
+	case plex.MediaTypeShow:
+		return "show"
+	case plex.MediaTypeSeason:
+		return "season"
+	case plex.MediaTypeEpisode:
+		return "episode"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,129 @@
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
+)
+
+// fakeMovie is a movie in the fake destination library
+type fakeMovie struct {
+	ratingKey string
+	file      string
+	updatedAt int
+}
+
+// fakeLibrary is a fake destination Plex server with one movie library (key "1") whose items can be replaced
+type fakeLibrary struct {
+	mu     sync.Mutex
+	movies []fakeMovie
+	client *plex.Client
+}
+
+// newFakeLibrary starts a fake destination server serving the library sections and their item listings
+func newFakeLibrary(t *testing.T, movies []fakeMovie) *fakeLibrary {
+	t.Helper()
+	fake := &fakeLibrary{movies: movies}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/identity":
+			_, _ = w.Write([]byte(`{"MediaContainer":{"machineIdentifier":"fake-machine"}}`))
+		case "/library/sections":
+			_, _ = w.Write([]byte(`{"MediaContainer":{"Directory":[{"key":"1","type":"movie","title":"Movies"}]}}`))
+		case "/library/sections/1/all":
+			updatedSince, _ := strconv.Atoi(r.URL.Query().Get("updatedAt>>"))
+			var items []plex.IndexItem
+			fake.mu.Lock()
+			for _, movie := range fake.movies {
+				if movie.updatedAt > updatedSince {
+					items = append(items, plex.IndexItem{
+						RatingKey: plex.FlexibleRatingKey{Value: movie.ratingKey},
+						Type:      "movie",
+						UpdatedAt: movie.updatedAt,
+						Media:     []plex.Media{{Part: []plex.Part{{File: movie.file}}}},
+					})
+				}
+			}
+			fake.mu.Unlock()
+			response := plex.IndexItemResponse{MediaContainer: plex.IndexItemContainer{Size: len(items), TotalSize: len(items), Metadata: items}}
+			_ = json.NewEncoder(w).Encode(response)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse fake server URL: %v", err)
+	}
+	client, err := plex.NewClient(&config.PlexServerConfig{
+		Host:  serverURL.Hostname(),
+		Port:  serverURL.Port(),
+		Token: "test-token",
+	}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("failed to connect to fake server: %v", err)
+	}
+	fake.client = client
+	return fake
+}
+
+// setMovies replaces the movies of the fake library
+func (f *fakeLibrary) setMovies(movies []fakeMovie) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.movies = movies
+}
+
+func TestDestinationIndexRefreshAddAndDelete(t *testing.T) {
+	fake := newFakeLibrary(t, []fakeMovie{
+		{ratingKey: "10", file: "/movies/Old Movie (2001).mkv", updatedAt: 100},
+		{ratingKey: "11", file: "/movies/Kept Movie (2002).mkv", updatedAt: 110},
+	})
+	log := logger.New("error")
+	index := NewDestinationIndex(fake.client, state.NewStore(t.TempDir(), log), log)
+	libraryTypes := map[string]bool{"movie": true}
+
+	if err := index.Refresh(libraryTypes); err != nil {
+		t.Fatalf("initial refresh failed: %v", err)
+	}
+	if _, found := index.LookupFilename("Old Movie (2001).mkv"); !found {
+		t.Fatalf("initial refresh did not index Old Movie")
+	}
+
+	// Movie 10 is deleted and movie 9 added with the same file name. Its updatedAt lies before the watermark,
+	// so the incremental listing doesn't return it, and the item count stays the same.
+	fake.setMovies([]fakeMovie{
+		{ratingKey: "9", file: "/movies/Old Movie (2001).mkv", updatedAt: 50},
+		{ratingKey: "11", file: "/movies/Kept Movie (2002).mkv", updatedAt: 110},
+	})
+	if err := index.Refresh(libraryTypes); err != nil {
+		t.Fatalf("incremental refresh failed: %v", err)
+	}
+
+	entries := index.Libraries["1"].Entries
+	if _, exists := entries["10"]; exists {
+		t.Errorf("deleted item 10 is still indexed")
+	}
+	if _, exists := entries["9"]; !exists {
+		t.Errorf("added item 9 is not indexed")
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d indexed items, want 2", len(entries))
+	}
+	entry, found := index.LookupFilename("Old Movie (2001).mkv")
+	if !found || entry.RatingKey != "9" {
+		t.Errorf("file name resolves to %+v, want rating key 9", entry)
+	}
+}
@@ -1,17 +1,22 @@
 package discovery
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/nullable-eth/syncarr/internal/logger"
 	"github.com/nullable-eth/syncarr/internal/plex"
 )
 
+// ErrIncompleteDiscovery is returned along with the discovered items when some labeled content could not be
+// listed or loaded. The items are still valid, but files missing from them must not be treated as orphaned.
+var ErrIncompleteDiscovery = errors.New("content discovery incomplete")
+
 // EnhancedMediaItem wraps Plex media items with library context and full metadata
 type EnhancedMediaItem struct {
-	Item      interface{} // plex.Movie, plex.TVShow, or plex.Episode with FULL metadata
+	Item      interface{} // plex.Movie, plex.TVShow, plex.Season, or plex.Episode with FULL metadata
 	LibraryID string      // Library ID for API operations
-	ItemType  string      // "movie", "show", "episode"
+	ItemType  string      // "movie", "show", "season", "episode"
 }
 
 // ContentDiscovery implements Phase 1: Complete Library Scanning
@@ -34,10 +39,14 @@ func NewContentDiscovery(sourceClient *plex.Client, syncLabel string, logger *lo
 //  1. List all items from all libraries on the source server with FULL metadata
 //  2. If any movie contains the sync tag, add it to the processing list with complete metadata
 //     If any TV show contains the sync label, list all episodes of all seasons and add them with complete metadata
+//     If only individual seasons or episodes of a show carry the sync label, add just those (partial shows)
+//
+// When labeled content fails to load, the rest is returned together with ErrIncompleteDiscovery.
 func (cd *ContentDiscovery) DiscoverSyncableContent() ([]*EnhancedMediaItem, error) {
 	cd.logger.Debug("Phase 1: Starting enhanced content discovery with full metadata loading")
 
 	var itemsToSync []*EnhancedMediaItem
+	failures := 0
 
 	// Get all libraries from source server
 	libraries, err := cd.sourceClient.GetLibraries()
@@ -60,6 +69,7 @@ func (cd *ContentDiscovery) DiscoverSyncableContent() ([]*EnhancedMediaItem, err
 				"library_id": library.Key,
 				"sync_label": cd.syncLabel,
 			}).Warn("Failed to get items with label")
+			failures++
 			continue
 		}
 
@@ -69,6 +79,14 @@ func (cd *ContentDiscovery) DiscoverSyncableContent() ([]*EnhancedMediaItem, err
 			"labeled_items": len(labeledItems),
 		}).Debug("Retrieved items with sync label, now loading full metadata")
 
+		// Shows labeled as a whole already cover all of their seasons and episodes
+		coveredShows := make(map[string]bool)
+		for _, item := range labeledItems {
+			if show, ok := item.(plex.TVShow); ok {
+				coveredShows[show.RatingKey.String()] = true
+			}
+		}
+
 		for i, item := range labeledItems {
 			cd.logger.WithFields(map[string]interface{}{
 				"progress": fmt.Sprintf("%d/%d", i+1, len(labeledItems)),
@@ -78,6 +96,7 @@ func (cd *ContentDiscovery) DiscoverSyncableContent() ([]*EnhancedMediaItem, err
 			enhancedItem, err := cd.loadFullMetadata(item, library.Key, library.Type)
 			if err != nil {
 				cd.logger.WithError(err).WithField("item", fmt.Sprintf("%T", item)).Warn("Failed to load full metadata for item")
+				failures++
 				continue
 			}
 
@@ -90,13 +109,107 @@ func (cd *ContentDiscovery) DiscoverSyncableContent() ([]*EnhancedMediaItem, err
 				}).Debug("Added item with full metadata to sync list")
 			}
 		}
+
+		// Seasons and episodes can carry the sync label on their own in show libraries
+		if library.Type == "show" {
+			partialItems, partialFailures := cd.discoverPartialShowContent(library, coveredShows)
+			itemsToSync = append(itemsToSync, partialItems...)
+			failures += partialFailures
+		}
 	}
 
 	cd.logger.WithField("total_items_to_sync", len(itemsToSync)).Debug("Phase 1 and 2: Enhanced content discovery with full metadata complete")
 
+	if failures > 0 {
+		return itemsToSync, fmt.Errorf("%w: %d label queries or items failed to load", ErrIncompleteDiscovery, failures)
+	}
 	return itemsToSync, nil
 }
 
+// discoverPartialShowContent finds labeled seasons and episodes whose show is not labeled as a whole.
+// Episodes inside a labeled season are skipped because the season item already covers them.
+// It also returns how many label queries or items failed to load.
+func (cd *ContentDiscovery) discoverPartialShowContent(library plex.Library, coveredShows map[string]bool) ([]*EnhancedMediaItem, int) {
+	var items []*EnhancedMediaItem
+	failures := 0
+	coveredSeasons := make(map[string]bool)
+	partialShows := make(map[string]bool)
+
+	seasons, err := cd.sourceClient.GetSeasonsWithLabel(library.Key, cd.syncLabel)
+	if err != nil {
+		cd.logger.WithError(err).WithFields(map[string]interface{}{
+			"library_id": library.Key,
+			"sync_label": cd.syncLabel,
+		}).Warn("Failed to get seasons with label")
+		failures++
+	}
+
+	for _, season := range seasons {
+		if coveredShows[season.ParentRatingKey.String()] {
+			continue
+		}
+
+		enhancedItem, err := cd.loadFullMetadata(season, library.Key, library.Type)
+		if err != nil {
+			cd.logger.WithError(err).WithField("season", season.Title).Warn("Failed to load full metadata for season")
+			failures++
+			continue
+		}
+
+		coveredSeasons[season.RatingKey.String()] = true
+		partialShows[season.ParentRatingKey.String()] = true
+		items = append(items, enhancedItem)
+
+		cd.logger.WithFields(map[string]interface{}{
+			"show":       season.ParentTitle,
+			"season":     season.Index,
+			"library_id": library.Key,
+		}).Debug("Added labeled season to sync list")
+	}
+
+	episodes, err := cd.sourceClient.GetEpisodesWithLabel(library.Key, cd.syncLabel)
+	if err != nil {
+		cd.logger.WithError(err).WithFields(map[string]interface{}{
+			"library_id": library.Key,
+			"sync_label": cd.syncLabel,
+		}).Warn("Failed to get episodes with label")
+		failures++
+	}
+
+	for _, episode := range episodes {
+		if coveredShows[episode.GrandparentRatingKey.String()] || coveredSeasons[episode.ParentRatingKey.String()] {
+			continue
+		}
+
+		enhancedItem, err := cd.loadFullMetadata(episode, library.Key, library.Type)
+		if err != nil {
+			cd.logger.WithError(err).WithField("episode", episode.Title).Warn("Failed to load full metadata for episode")
+			failures++
+			continue
+		}
+
+		partialShows[episode.GrandparentRatingKey.String()] = true
+		items = append(items, enhancedItem)
+
+		cd.logger.WithFields(map[string]interface{}{
+			"show":       episode.GrandparentTitle,
+			"season":     episode.ParentIndex,
+			"episode":    episode.Index,
+			"library_id": library.Key,
+		}).Debug("Added labeled episode to sync list")
+	}
+
+	if len(items) > 0 {
+		cd.logger.WithFields(map[string]interface{}{
+			"library_id":    library.Key,
+			"partial_shows": len(partialShows),
+			"items":         len(items),
+		}).Debug("Discovered labeled seasons and episodes of partially synced shows")
+	}
+
+	return items, failures
+}
+
 // GetItemFilePaths extracts file paths from a media item
 func (cd *ContentDiscovery) GetItemFilePaths(item interface{}) ([]string, error) {
 	var filePaths []string
@@ -118,6 +231,26 @@ func (cd *ContentDiscovery) GetItemFilePaths(item interface{}) ([]string, error)
 				}
 			}
 		}
+	case plex.Season:
+		episodes, err := cd.sourceClient.GetSeasonEpisodes(v.RatingKey.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get episodes for season %s: %w", v.Title, err)
+		}
+		for _, episode := range episodes {
+			episodePaths, err := cd.GetItemFilePaths(episode)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get file paths for episode %s of season %s: %w", episode.Title, v.Title, err)
+			}
+			filePaths = append(filePaths, episodePaths...)
+		}
+	case plex.Episode:
+		for _, media := range v.Media {
+			for _, part := range media.Part {
+				if part.File != "" {
+					filePaths = append(filePaths, part.File)
+				}
+			}
+		}
 	}
 
 	return filePaths, nil
@@ -155,11 +288,24 @@ func (cd *ContentDiscovery) loadFullMetadata(item interface{}, libraryID, librar
 			ItemType:  "show",
 		}, nil
 
+	case plex.Season:
+		fullSeason, err := cd.sourceClient.GetSeasonDetails(ratingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load full season metadata: %w", err)
+		}
+		return &EnhancedMediaItem{
+			Item:      *fullSeason,
+			LibraryID: libraryID,
+			ItemType:  "season",
+		}, nil
+
 	case plex.Episode:
-		// For episodes, we could add a GetEpisodeDetails method if needed
-		// For now, episodes from GetItemsWithLabel should have sufficient metadata
+		fullEpisode, err := cd.sourceClient.GetEpisodeDetails(ratingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load full episode metadata: %w", err)
+		}
 		return &EnhancedMediaItem{
-			Item:      item,
+			Item:      *fullEpisode,
 			LibraryID: libraryID,
 			ItemType:  "episode",
 		}, nil
@@ -176,6 +322,8 @@ func (cd *ContentDiscovery) getRatingKey(item interface{}) string {
 		return v.RatingKey.String()
 	case plex.TVShow:
 		return v.RatingKey.String()
+	case plex.Season:
+		return v.RatingKey.String()
 	case plex.Episode:
 		return v.RatingKey.String()
 	default:
@@ -190,6 +338,8 @@ func (cd *ContentDiscovery) getItemTitle(item interface{}) string {
 		return v.Title
 	case plex.TVShow:
 		return v.Title
+	case plex.Season:
+		return fmt.Sprintf("%s - %s", v.ParentTitle, v.Title)
 	case plex.Episode:
 		return v.Title
 	default:
@@ -10,15 +10,17 @@ import (
 
 // LibraryManager handles Phase 5: Library refresh and monitoring
 type LibraryManager struct {
-	destClient *plex.Client
-	logger     *logger.Logger
+	destClient   *plex.Client
+	forceRefresh bool // Force a metadata refresh after the scans; unlocked fields get agent values again
+	logger       *logger.Logger
 }
 
 // NewLibraryManager creates a new library manager
-func NewLibraryManager(destClient *plex.Client, log *logger.Logger) *LibraryManager {
+func NewLibraryManager(destClient *plex.Client, forceRefresh bool, log *logger.Logger) *LibraryManager {
 	return &LibraryManager{
-		destClient: destClient,
-		logger:     log,
+		destClient:   destClient,
+		forceRefresh: forceRefresh,
+		logger:       log,
 	}
 }
 
@@ -85,6 +87,11 @@ func (lm *LibraryManager) TriggerRefreshAndWait() error {
 		return fmt.Errorf("library scan failed: %w", err)
 	}
 
+	if !lm.forceRefresh {
+		lm.logger.Info("Library scans completed, forced metadata refresh disabled")
+		return nil
+	}
+
 	lm.logger.Info("Library scans completed, now triggering metadata refresh for all libraries")
 
 	// Trigger metadata refresh for all libraries to populate initial metadata
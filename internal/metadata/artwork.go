@@ -0,0 +1,178 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
+)
+
+// artworkCacheDocument is the state store document name for synced artwork
+const artworkCacheDocument = "artwork-cache"
+
+// artworkEntry records the artwork paths on both servers after a sync and the hash of the copied content
+type artworkEntry struct {
+	SourcePath string `json:"sourcePath"`
+	DestPath   string `json:"destPath"`
+	Hash       string `json:"hash"` // SHA-256 of the artwork content
+}
+
+// artworkCache remembers synced artwork, so unchanged posters, backgrounds and themes aren't re-uploaded
+type artworkCache struct {
+	mu      sync.Mutex
+	Entries map[string]*artworkEntry `json:"entries"` // Keyed by baselineKey with the artwork kind as field
+}
+
+// loadArtworkCache loads the persisted artwork cache, starting empty when there is none
+func loadArtworkCache(store *state.Store, log *logger.Logger) *artworkCache {
+	cache := &artworkCache{Entries: make(map[string]*artworkEntry)}
+	if err := store.Load(artworkCacheDocument, cache); err != nil {
+		log.WithError(err).Warn("Failed to load artwork cache, artwork will be compared again")
+	}
+	if cache.Entries == nil {
+		cache.Entries = make(map[string]*artworkEntry)
+	}
+	return cache
+}
+
+// get returns the cached entry of one artwork kind of an item pair
+func (c *artworkCache) get(key string) (artworkEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.Entries[key]
+	if !ok {
+		return artworkEntry{}, false
+	}
+	return *entry, true
+}
+
+// set records the artwork state of one kind of an item pair
+func (c *artworkCache) set(key string, entry artworkEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Entries[key] = &entry
+}
+
+// syncArtwork copies the selected poster, background and theme between an item pair according to the artwork policy
+func (s *Synchronizer) syncArtwork(source, dest itemFields, sourceNewer bool) []string {
+	if s.policies[config.FieldGroupArtwork].Direction == config.DirectionOff {
+		return nil
+	}
+
+	var errors []string
+	kinds := []struct {
+		kind                 string
+		sourcePath, destPath string
+	}{
+		{plex.ArtworkPoster, source.thumb, dest.thumb},
+		{plex.ArtworkArt, source.art, dest.art},
+		{plex.ArtworkTheme, source.theme, dest.theme},
+	}
+	for _, k := range kinds {
+		if err := s.syncArtworkKind(k.kind, k.sourcePath, k.destPath, source, dest, sourceNewer); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", k.kind, err))
+		}
+	}
+	return errors
+}
+
+// syncArtworkKind syncs one kind of artwork. Changes are detected by the artwork path, which Plex changes
+// whenever another image is selected; the content hash avoids uploading an image the other side already has.
+func (s *Synchronizer) syncArtworkKind(kind, sourcePath, destPath string, source, dest itemFields, sourceNewer bool) error {
+	key := baselineKey("", source.ratingKey, dest.ratingKey, kind)
+	entry, known := s.artwork.get(key)
+
+	sourceChanged := !known || entry.SourcePath != sourcePath
+	destChanged := !known || entry.DestPath != destPath
+	action := s.decide(config.FieldGroupArtwork, sourceChanged, destChanged, sourceNewer)
+
+	fromClient, toClient := s.sourceClient, s.destClient
+	fromPath, toPath, from, to, toChanged, direction := sourcePath, destPath, source, dest, destChanged, "source_to_dest"
+	switch {
+	case action.updateDest:
+	case action.updateSource:
+		fromClient, toClient = s.destClient, s.sourceClient
+		fromPath, toPath, from, to, toChanged, direction = destPath, sourcePath, dest, source, sourceChanged, "dest_to_source"
+	default:
+		return nil
+	}
+
+	// Nothing selected on the sending side: there is nothing to copy
+	if fromPath == "" {
+		return nil
+	}
+
+	data, err := fromClient.DownloadArtwork(fromPath)
+	if err != nil {
+		return err
+	}
+	hash := artworkHash(data)
+
+	// Without a cache entry (first run, lost state) the receiving side may already show the same image
+	if !known && toPath != "" {
+		existing, err := toClient.DownloadArtwork(toPath)
+		if err != nil {
+			return err
+		}
+		if artworkHash(existing) == hash {
+			s.artwork.set(key, artworkEntry{SourcePath: sourcePath, DestPath: destPath, Hash: hash})
+			s.logger.WithFields(map[string]interface{}{
+				"kind":       kind,
+				"rating_key": to.ratingKey,
+			}).Debug("Artwork already present on the receiving server, skipping upload")
+			return nil
+		}
+	}
+
+	if known && entry.Hash == hash && !toChanged {
+		s.artwork.set(key, artworkEntry{SourcePath: sourcePath, DestPath: destPath, Hash: hash})
+		s.logger.WithFields(map[string]interface{}{
+			"kind":       kind,
+			"rating_key": to.ratingKey,
+		}).Debug("Artwork content unchanged, skipping upload")
+		return nil
+	}
+
+	if err := toClient.UploadArtwork(to.ratingKey, kind, data); err != nil {
+		return err
+	}
+	field := map[string]string{plex.ArtworkPoster: "thumb", plex.ArtworkArt: "art", plex.ArtworkTheme: "theme"}[kind]
+	if s.shouldLock(field, from) {
+		if err := toClient.LockField(to.ratingKey, to.libraryID, field, to.mediaType); err != nil {
+			return err
+		}
+	}
+
+	// The upload gives the receiving item a new artwork path; record it so it doesn't look like an edit next cycle
+	paths, err := toClient.GetArtworkPaths(to.ratingKey)
+	if err != nil {
+		return fmt.Errorf("artwork uploaded but its new path could not be read: %w", err)
+	}
+	newPath := map[string]string{plex.ArtworkPoster: paths.Thumb, plex.ArtworkArt: paths.Art, plex.ArtworkTheme: paths.Theme}[kind]
+	if direction == "source_to_dest" {
+		s.artwork.set(key, artworkEntry{SourcePath: sourcePath, DestPath: newPath, Hash: hash})
+	} else {
+		s.artwork.set(key, artworkEntry{SourcePath: newPath, DestPath: destPath, Hash: hash})
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"kind":              kind,
+		"direction":         direction,
+		"source_rating_key": source.ratingKey,
+		"dest_rating_key":   dest.ratingKey,
+		"size_bytes":        len(data),
+	}).Info("Synced artwork")
+
+	return nil
+}
+
+// artworkHash returns the hex SHA-256 of artwork content
+func artworkHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,76 @@
+package metadata
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+func TestSyncArtworkKindWithoutCacheEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		destPoster string
+		wantUpload bool
+	}{
+		{name: "same image on destination is recorded without upload", destPoster: "poster-a", wantUpload: false},
+		{name: "different image on destination is replaced", destPoster: "poster-b", wantUpload: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newFakePlex(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("poster-a"))
+			})
+			dest := newFakePlex(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/library/metadata/2/thumb/200":
+					_, _ = w.Write([]byte(tt.destPoster))
+				case r.Method == http.MethodGet && r.URL.Path == "/library/metadata/2":
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"MediaContainer":{"Metadata":[{"thumb":"/library/metadata/2/thumb/300"}]}}`))
+				}
+			})
+
+			s := &Synchronizer{
+				sourceClient: source.client,
+				destClient:   dest.client,
+				logger:       logger.New("error"),
+				artwork:      &artworkCache{Entries: make(map[string]*artworkEntry)},
+				policies: map[string]config.SyncPolicy{
+					config.FieldGroupArtwork: {Direction: config.DirectionSourceToDest, Conflict: config.ConflictSource},
+				},
+			}
+			sourceItem := itemFields{ratingKey: "1", mediaType: "movie", thumb: "/library/metadata/1/thumb/100"}
+			destItem := itemFields{ratingKey: "2", mediaType: "movie", thumb: "/library/metadata/2/thumb/200"}
+
+			if err := s.syncArtworkKind(plex.ArtworkPoster, sourceItem.thumb, destItem.thumb, sourceItem, destItem, true); err != nil {
+				t.Fatalf("syncArtworkKind() failed: %v", err)
+			}
+
+			uploaded := false
+			for _, r := range dest.requests {
+				if r.Method == http.MethodPost {
+					uploaded = true
+				}
+			}
+			if uploaded != tt.wantUpload {
+				t.Errorf("uploaded = %v, want %v", uploaded, tt.wantUpload)
+			}
+
+			entry, known := s.artwork.get(baselineKey("", "1", "2", plex.ArtworkPoster))
+			if !known {
+				t.Fatalf("no cache entry recorded")
+			}
+			wantDestPath := "/library/metadata/2/thumb/200"
+			if tt.wantUpload {
+				wantDestPath = "/library/metadata/2/thumb/300"
+			}
+			if entry.DestPath != wantDestPath || entry.Hash != artworkHash([]byte("poster-a")) {
+				t.Errorf("cache entry = %+v, want destination path %s and the hash of poster-a", entry, wantDestPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package metadata
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// LibraryPair links a source library section to the destination section its items were matched in
+type LibraryPair struct {
+	SourceLibraryID string
+	DestLibraryID   string
+}
+
+// SyncCollections syncs collection-level details (sort title, summary, sort order and poster) of collections
+// that exist in both libraries of each pair, and recreates source smart collections missing on the destination.
+// Regular collections are created on the destination by the membership tags synced with each item.
+func (s *Synchronizer) SyncCollections(pairs []LibraryPair) error {
+	if s.policies[config.FieldGroupCollections].Direction == config.DirectionOff || len(pairs) == 0 {
+		return nil
+	}
+
+	destLibraries, err := s.destClient.GetLibraries()
+	if err != nil {
+		return fmt.Errorf("failed to get destination libraries: %w", err)
+	}
+	destLibraryTypes := make(map[string]string, len(destLibraries))
+	for _, library := range destLibraries {
+		destLibraryTypes[library.Key] = library.Type
+	}
+
+	var syncErrors []string
+	for _, pair := range pairs {
+		if err := s.syncLibraryCollections(pair, destLibraryTypes[pair.DestLibraryID]); err != nil {
+			syncErrors = append(syncErrors, fmt.Sprintf("library %s: %v", pair.SourceLibraryID, err))
+		}
+	}
+
+	if len(syncErrors) > 0 {
+		return fmt.Errorf("collection sync errors: %v", syncErrors)
+	}
+	return nil
+}
+
+// syncLibraryCollections syncs the collections of one library pair, matching them by title
+func (s *Synchronizer) syncLibraryCollections(pair LibraryPair, destLibraryType string) error {
+	sourceCollections, err := s.sourceClient.GetCollections(pair.SourceLibraryID)
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	destCollections, err := s.destClient.GetCollections(pair.DestLibraryID)
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+
+	destByTitle := make(map[string]plex.PlexCollection, len(destCollections))
+	for _, collection := range destCollections {
+		destByTitle[strings.ToLower(collection.Title)] = collection
+	}
+
+	var syncErrors []string
+	for _, sourceCollection := range sourceCollections {
+		destCollection, found := destByTitle[strings.ToLower(sourceCollection.Title)]
+		if !found {
+			if !sourceCollection.Smart.Value || s.policies[config.FieldGroupCollections].Direction == config.DirectionDestToSource {
+				continue
+			}
+			created, err := s.recreateSmartCollection(pair, destLibraryType, sourceCollection)
+			if err != nil {
+				syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", sourceCollection.Title, err))
+				continue
+			}
+			if created == nil {
+				continue
+			}
+			destCollection = *created
+		}
+
+		if errs := s.syncCollectionDetails(pair, sourceCollection, destCollection); len(errs) > 0 {
+			syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", sourceCollection.Title, errs))
+		}
+	}
+
+	if len(syncErrors) > 0 {
+		return fmt.Errorf("%v", syncErrors)
+	}
+	return nil
+}
+
+// recreateSmartCollection creates a source smart collection in the destination library from its filter.
+// It returns nil without error when the destination library cannot hold the collection's item type.
+func (s *Synchronizer) recreateSmartCollection(pair LibraryPair, destLibraryType string, collection plex.PlexCollection) (*plex.PlexCollection, error) {
+	compatible := map[string]string{"movie": "movie", "show": "show", "season": "show", "episode": "show"}[collection.Subtype]
+	if compatible == "" || compatible != destLibraryType {
+		s.logger.WithFields(map[string]interface{}{
+			"collection":        collection.Title,
+			"subtype":           collection.Subtype,
+			"dest_library_id":   pair.DestLibraryID,
+			"dest_library_type": destLibraryType,
+		}).Debug("Destination library is not compatible with smart collection, skipping")
+		return nil, nil
+	}
+
+	_, filter, found := strings.Cut(collection.Content, "?")
+	if !found {
+		return nil, fmt.Errorf("smart collection has no filter definition")
+	}
+	query, err := url.ParseQuery(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smart collection filter: %w", err)
+	}
+	mediaType, err := strconv.Atoi(query.Get("type"))
+	if err != nil {
+		return nil, fmt.Errorf("smart collection filter has no item type")
+	}
+
+	// Tag conditions reference per-server tag IDs; without a translation the filter would match other tags
+	translated, err := s.translateSmartFilter(pair, mediaType, filter)
+	if err != nil {
+		s.logger.WithError(err).WithFields(map[string]interface{}{
+			"collection":      collection.Title,
+			"dest_library_id": pair.DestLibraryID,
+		}).Warn("Cannot translate smart collection filter to the destination, skipping")
+		return nil, nil
+	}
+
+	return s.destClient.CreateSmartCollection(pair.DestLibraryID, mediaType, collection.Title, translated)
+}
+
+// smartFilterTagFields are the smart collection filter fields whose values are tag IDs
+var smartFilterTagFields = map[string]bool{
+	"actor": true, "collection": true, "country": true, "director": true,
+	"genre": true, "label": true, "producer": true, "writer": true,
+}
+
+// smartFilterTypes maps the item type prefix of an advanced filter field (e.g. "episode.director") to its media type
+var smartFilterTypes = map[string]int{
+	"movie": plex.MediaTypeMovie, "show": plex.MediaTypeShow, "season": plex.MediaTypeSeason, "episode": plex.MediaTypeEpisode,
+}
+
+// translateSmartFilter rewrites the tag IDs of a smart collection filter from source to destination tag IDs,
+// matching the tags by name. The order of the filter's parameters is kept, since advanced filters group their
+// conditions by it. A tag the destination does not have is an error.
+func (s *Synchronizer) translateSmartFilter(pair LibraryPair, mediaType int, filter string) (string, error) {
+	sourceTags := &tagIndex{client: s.sourceClient, libraryID: pair.SourceLibraryID}
+	destTags := &tagIndex{client: s.destClient, libraryID: pair.DestLibraryID}
+
+	parts := strings.Split(filter, "&")
+	for i, part := range parts {
+		rawKey, rawValue, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return "", fmt.Errorf("invalid filter field %q: %w", rawKey, err)
+		}
+
+		// Operators other than "=" end up in the key, e.g. "genre!=5" splits into "genre!" and "5"
+		field, fieldType := strings.TrimRight(key, "!<>"), mediaType
+		if prefix, name, found := strings.Cut(field, "."); found {
+			if fieldType, found = smartFilterTypes[prefix]; !found {
+				continue
+			}
+			field = name
+		}
+		if !smartFilterTagFields[field] {
+			continue
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s filter value %q: %w", field, rawValue, err)
+		}
+		ids := strings.Split(value, ",")
+		for j, id := range ids {
+			if _, err := strconv.Atoi(id); err != nil {
+				continue // Not a tag ID
+			}
+			title, err := sourceTags.title(field, fieldType, id)
+			if err != nil {
+				return "", fmt.Errorf("source: %w", err)
+			}
+			if ids[j], err = destTags.key(field, fieldType, title); err != nil {
+				return "", fmt.Errorf("destination: %w", err)
+			}
+		}
+		parts[i] = rawKey + "=" + url.QueryEscape(strings.Join(ids, ","))
+	}
+
+	return strings.Join(parts, "&"), nil
+}
+
+// tagIndex looks up the tag values of a library's fields, fetching each field's values once
+type tagIndex struct {
+	client    *plex.Client
+	libraryID string
+	values    map[string][]plex.TagValue // Keyed by field and media type
+}
+
+// lookup returns the values of a tag field for items of the given type
+func (t *tagIndex) lookup(field string, mediaType int) ([]plex.TagValue, error) {
+	cacheKey := fmt.Sprintf("%s|%d", field, mediaType)
+	if values, cached := t.values[cacheKey]; cached {
+		return values, nil
+	}
+	values, err := t.client.GetTagValues(t.libraryID, field, mediaType)
+	if err != nil {
+		return nil, err
+	}
+	if t.values == nil {
+		t.values = make(map[string][]plex.TagValue)
+	}
+	t.values[cacheKey] = values
+	return values, nil
+}
+
+// title returns the name of the tag with the given ID
+func (t *tagIndex) title(field string, mediaType int, key string) (string, error) {
+	values, err := t.lookup(field, mediaType)
+	if err != nil {
+		return "", err
+	}
+	for _, value := range values {
+		if value.Key == key {
+			return value.Title, nil
+		}
+	}
+	return "", fmt.Errorf("no %s with ID %s", field, key)
+}
+
+// key returns the ID of the tag with the given name, compared case-insensitively
+func (t *tagIndex) key(field string, mediaType int, title string) (string, error) {
+	values, err := t.lookup(field, mediaType)
+	if err != nil {
+		return "", err
+	}
+	for _, value := range values {
+		if strings.EqualFold(value.Title, title) {
+			return value.Key, nil
+		}
+	}
+	return "", fmt.Errorf("no %s named %q", field, title)
+}
+
+// syncCollectionDetails reconciles the sort title, summary, sort order and poster of a collection pair
+func (s *Synchronizer) syncCollectionDetails(pair LibraryPair, sourceCollection, destCollection plex.PlexCollection) []string {
+	source := itemFields{
+		ratingKey: sourceCollection.RatingKey.String(),
+		libraryID: pair.SourceLibraryID,
+		mediaType: "collection",
+		title:     sourceCollection.Title,
+		summary:   sourceCollection.Summary,
+		thumb:     sourceCollection.Thumb,
+		updatedAt: sourceCollection.UpdatedAt,
+		locked:    lockedFields(sourceCollection.Field),
+	}
+	dest := itemFields{
+		ratingKey: destCollection.RatingKey.String(),
+		libraryID: pair.DestLibraryID,
+		mediaType: "collection",
+		title:     destCollection.Title,
+		summary:   destCollection.Summary,
+		thumb:     destCollection.Thumb,
+		updatedAt: destCollection.UpdatedAt,
+		locked:    lockedFields(destCollection.Field),
+	}
+	sourceNewer := source.updatedAt > dest.updatedAt
+
+	var errors []string
+	if err := s.syncText(config.FieldGroupCollections, "titleSort", source, dest, sourceCollection.TitleSort, destCollection.TitleSort, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("sort title: %v", err))
+	}
+	if err := s.syncText(config.FieldGroupCollections, "summary", source, dest, source.summary, dest.summary, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("summary: %v", err))
+	}
+	// The sort order is a collection setting; 0 (release date) is a real value here, so it is always pushed
+	sourceSort, destSort := strconv.Itoa(sourceCollection.CollectionSort.Value), strconv.Itoa(destCollection.CollectionSort.Value)
+	if err := s.syncPref(config.FieldGroupCollections, "collectionSort", source, dest, sourceSort, destSort, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("sort order: %v", err))
+	}
+	if s.policies[config.FieldGroupArtwork].Direction != config.DirectionOff {
+		if err := s.syncArtworkKind(plex.ArtworkPoster, source.thumb, dest.thumb, source, dest, sourceNewer); err != nil {
+			errors = append(errors, fmt.Sprintf("poster: %v", err))
+		}
+	}
+
+	return errors
+}
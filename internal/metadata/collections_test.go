@@ -0,0 +1,80 @@
+package metadata
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// tagValuesHandler answers tag value listings (e.g. /library/sections/1/genre) from fixed values per field
+func tagValuesHandler(values map[string][]plex.TagValue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var response plex.TagValuesResponse
+		response.MediaContainer.Directory = values[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+func TestTranslateSmartFilter(t *testing.T) {
+	source := newFakePlex(t, tagValuesHandler(map[string][]plex.TagValue{
+		"genre":    {{Key: "5", Title: "Action"}, {Key: "6", Title: "Drama"}},
+		"label":    {{Key: "9", Title: "Kids"}, {Key: "10", Title: "Source Only"}},
+		"director": {{Key: "40", Title: "Ridley Scott"}},
+	}))
+	dest := newFakePlex(t, tagValuesHandler(map[string][]plex.TagValue{
+		"genre":    {{Key: "17", Title: "action"}, {Key: "23", Title: "Drama"}},
+		"label":    {{Key: "31", Title: "Kids"}},
+		"director": {{Key: "88", Title: "Ridley Scott"}},
+	}))
+	s := &Synchronizer{sourceClient: source.client, destClient: dest.client}
+	pair := LibraryPair{SourceLibraryID: "1", DestLibraryID: "2"}
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "tag IDs are translated by name",
+			filter: "type=1&sort=titleSort&genre=5%2C6",
+			want:   "type=1&sort=titleSort&genre=17%2C23",
+		},
+		{
+			name:   "operators and parameter order are kept",
+			filter: "type=1&push=1&label!=9&or=1&year>>=2000&pop=1",
+			want:   "type=1&push=1&label!=31&or=1&year>>=2000&pop=1",
+		},
+		{
+			name:   "advanced filter fields name their item type",
+			filter: "type=4&episode.director=40",
+			want:   "type=4&episode.director=88",
+		},
+		{
+			name:    "tag missing on the destination",
+			filter:  "type=1&label=10",
+			wantErr: true,
+		},
+		{
+			name:    "unknown source tag ID",
+			filter:  "type=1&genre=99",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.translateSmartFilter(pair, plex.MediaTypeMovie, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("translateSmartFilter(%q) error = %v, wantErr %t", tt.filter, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("translateSmartFilter(%q) = %q, want %q", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package metadata
+
+import "fmt"
+
+// SyncMarkers copies the intro and credits markers of a movie or episode to the destination item when it has
+// none of that type, so marker analysis can stay off on the destination server. Chapters are part of the
+// transferred file and cannot be written through the Plex API, so differing chapter sources are only logged.
+func (s *Synchronizer) SyncMarkers(sourceRatingKey, destRatingKey string) error {
+	if !s.syncMarkers {
+		return nil
+	}
+
+	sourceMarkers, err := s.sourceClient.GetMarkers(sourceRatingKey)
+	if err != nil {
+		return fmt.Errorf("failed to get source markers: %w", err)
+	}
+	destMarkers, err := s.destClient.GetMarkers(destRatingKey)
+	if err != nil {
+		return fmt.Errorf("failed to get destination markers: %w", err)
+	}
+
+	destTypes := make(map[string]bool)
+	for _, marker := range destMarkers.Marker {
+		destTypes[marker.Type] = true
+	}
+
+	copied := 0
+	for _, marker := range sourceMarkers.Marker {
+		if (marker.Type != "intro" && marker.Type != "credits") || destTypes[marker.Type] {
+			continue
+		}
+		if err := s.destClient.CreateMarker(destRatingKey, marker); err != nil {
+			return err
+		}
+		copied++
+	}
+
+	if copied > 0 {
+		s.logger.WithFields(map[string]interface{}{
+			"source_rating_key": sourceRatingKey,
+			"dest_rating_key":   destRatingKey,
+			"markers":           copied,
+		}).Info("Copied markers to destination")
+	}
+
+	if sourceMarkers.ChapterSource != destMarkers.ChapterSource {
+		s.logger.WithFields(map[string]interface{}{
+			"source_rating_key":     sourceRatingKey,
+			"dest_rating_key":       destRatingKey,
+			"source_chapter_source": sourceMarkers.ChapterSource,
+			"dest_chapter_source":   destMarkers.ChapterSource,
+		}).Debug("Chapter sources differ; chapters cannot be synced through the Plex API")
+	}
+
+	return nil
+}
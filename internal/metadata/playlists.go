@@ -0,0 +1,205 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nullable-eth/syncarr/internal/discovery"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// SyncPlaylists copies the selected source playlists to the destination, creating them when missing.
+// Playlists are selected by title or by carrying the sync label. Entries are matched to destination items through
+// matcher; entries whose item hasn't been transferred yet are skipped and picked up in a later cycle.
+func (s *Synchronizer) SyncPlaylists(matcher *discovery.ContentMatcher) error {
+	sourcePlaylists, err := s.sourceClient.GetPlaylists()
+	if err != nil {
+		return fmt.Errorf("failed to get source playlists: %w", err)
+	}
+
+	var selected []plex.Playlist
+	for _, playlist := range sourcePlaylists {
+		if s.playlistSelected(playlist) {
+			selected = append(selected, playlist)
+		}
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	destPlaylists, err := s.destClient.GetPlaylists()
+	if err != nil {
+		return fmt.Errorf("failed to get destination playlists: %w", err)
+	}
+	destByTitle := make(map[string]*plex.Playlist, len(destPlaylists))
+	for i := range destPlaylists {
+		if !destPlaylists[i].Smart.Value {
+			destByTitle[strings.ToLower(destPlaylists[i].Title)] = &destPlaylists[i]
+		}
+	}
+
+	var syncErrors []string
+	for _, playlist := range selected {
+		if err := s.syncPlaylist(playlist, destByTitle[strings.ToLower(playlist.Title)], matcher); err != nil {
+			syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", playlist.Title, err))
+		}
+	}
+
+	if len(syncErrors) > 0 {
+		return fmt.Errorf("playlist sync errors: %v", syncErrors)
+	}
+	return nil
+}
+
+// playlistSelected reports whether a source playlist is named in the config or carries the sync label
+func (s *Synchronizer) playlistSelected(playlist plex.Playlist) bool {
+	if s.playlists[strings.ToLower(playlist.Title)] {
+		return true
+	}
+	for _, label := range playlist.Label {
+		if strings.EqualFold(label.Tag, s.syncLabel) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncPlaylist brings one destination playlist in line with its source playlist. Smart source playlists are
+// copied as regular playlists holding their current entries.
+func (s *Synchronizer) syncPlaylist(playlist plex.Playlist, destPlaylist *plex.Playlist, matcher *discovery.ContentMatcher) error {
+	sourceItems, err := s.sourceClient.GetPlaylistItems(playlist.RatingKey.String())
+	if err != nil {
+		return err
+	}
+
+	matched := matcher.MatchPlaylistItems(sourceItems)
+	var wanted []string
+	seen := make(map[string]bool)
+	for _, item := range sourceItems {
+		destKey, found := matched[item.RatingKey.String()]
+		if !found || seen[destKey] {
+			continue
+		}
+		seen[destKey] = true
+		wanted = append(wanted, destKey)
+	}
+
+	logFields := map[string]interface{}{
+		"playlist":     playlist.Title,
+		"source_items": len(sourceItems),
+		"dest_items":   len(wanted),
+	}
+	if len(wanted) < len(sourceItems) {
+		s.logger.WithFields(logFields).Debug("Skipping playlist entries that have no destination copy yet")
+	}
+
+	if destPlaylist == nil {
+		if len(wanted) == 0 {
+			s.logger.WithFields(logFields).Debug("No playlist entries transferred yet, not creating playlist")
+			return nil
+		}
+		created, err := s.destClient.CreatePlaylist(playlist.Title, wanted)
+		if err != nil {
+			return err
+		}
+		if playlist.Summary != "" {
+			if err := s.destClient.UpdatePlaylist(created.RatingKey.String(), playlist.Title, playlist.Summary); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	destKey := destPlaylist.RatingKey.String()
+	if destPlaylist.Title != playlist.Title || destPlaylist.Summary != playlist.Summary {
+		if err := s.destClient.UpdatePlaylist(destKey, playlist.Title, playlist.Summary); err != nil {
+			return err
+		}
+	}
+
+	destItems, err := s.destClient.GetPlaylistItems(destKey)
+	if err != nil {
+		return err
+	}
+	return s.reconcilePlaylistItems(playlist.Title, destKey, destItems, wanted)
+}
+
+// reconcilePlaylistItems removes unwanted and duplicate destination entries, appends missing ones
+// and moves entries until the destination order matches wanted
+func (s *Synchronizer) reconcilePlaylistItems(title, destKey string, destItems []plex.PlaylistItem, wanted []string) error {
+	wantedSet := make(map[string]bool, len(wanted))
+	for _, key := range wanted {
+		wantedSet[key] = true
+	}
+
+	var current []plex.PlaylistItem
+	present := make(map[string]bool)
+	removed := 0
+	for _, item := range destItems {
+		key := item.RatingKey.String()
+		if !wantedSet[key] || present[key] {
+			if err := s.destClient.RemovePlaylistItem(destKey, item.PlaylistItemID.Value); err != nil {
+				return err
+			}
+			removed++
+			continue
+		}
+		present[key] = true
+		current = append(current, item)
+	}
+
+	var missing []string
+	for _, key := range wanted {
+		if !present[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		if err := s.destClient.AddPlaylistItems(destKey, missing); err != nil {
+			return err
+		}
+		// Added entries only get their playlist item IDs on the server, which the moves below need
+		refreshed, err := s.destClient.GetPlaylistItems(destKey)
+		if err != nil {
+			return err
+		}
+		current = refreshed
+	}
+
+	moved := 0
+	for i, key := range wanted {
+		if i < len(current) && current[i].RatingKey.String() == key {
+			continue
+		}
+		j := i + 1
+		for j < len(current) && current[j].RatingKey.String() != key {
+			j++
+		}
+		if j >= len(current) {
+			continue
+		}
+
+		afterID := 0
+		if i > 0 {
+			afterID = current[i-1].PlaylistItemID.Value
+		}
+		if err := s.destClient.MovePlaylistItem(destKey, current[j].PlaylistItemID.Value, afterID); err != nil {
+			return err
+		}
+
+		item := current[j]
+		copy(current[i+1:j+1], current[i:j])
+		current[i] = item
+		moved++
+	}
+
+	if removed > 0 || len(missing) > 0 || moved > 0 {
+		s.logger.WithFields(map[string]interface{}{
+			"playlist": title,
+			"removed":  removed,
+			"added":    len(missing),
+			"moved":    moved,
+		}).Info("Synced playlist")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package metadata
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// fakePlaylist is the destination side of a playlist: its entries in order, and the edits made to it
+type fakePlaylist struct {
+	ratingKeys []string
+	itemIDs    []int
+	nextID     int
+	calls      []string
+}
+
+// serve answers the playlist item endpoints of playlist 7, applying removals, additions and moves
+func (p *fakePlaylist) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/playlists/7/items")
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		entries := make([]string, len(p.ratingKeys))
+		for i := range p.ratingKeys {
+			entries[i] = fmt.Sprintf(`{"ratingKey":"%s","playlistItemID":%d}`, p.ratingKeys[i], p.itemIDs[i])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"MediaContainer":{"Metadata":[%s]}}`, strings.Join(entries, ","))
+
+	case r.Method == http.MethodPut && path == "":
+		uri := r.URL.Query().Get("uri")
+		keys := strings.Split(uri[strings.LastIndex(uri, "/")+1:], ",")
+		for _, key := range keys {
+			p.nextID++
+			p.ratingKeys, p.itemIDs = append(p.ratingKeys, key), append(p.itemIDs, p.nextID)
+		}
+		p.calls = append(p.calls, "add "+strings.Join(keys, ","))
+
+	case r.Method == http.MethodDelete:
+		id, _ := strconv.Atoi(strings.TrimPrefix(path, "/"))
+		i := p.index(id)
+		p.ratingKeys, p.itemIDs = append(p.ratingKeys[:i], p.ratingKeys[i+1:]...), append(p.itemIDs[:i], p.itemIDs[i+1:]...)
+		p.calls = append(p.calls, fmt.Sprintf("remove %d", id))
+
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/move"):
+		id, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/move"))
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		i := p.index(id)
+		key := p.ratingKeys[i]
+		p.ratingKeys, p.itemIDs = append(p.ratingKeys[:i], p.ratingKeys[i+1:]...), append(p.itemIDs[:i], p.itemIDs[i+1:]...)
+		at := 0
+		if after > 0 {
+			at = p.index(after) + 1
+		}
+		p.ratingKeys = append(p.ratingKeys[:at], append([]string{key}, p.ratingKeys[at:]...)...)
+		p.itemIDs = append(p.itemIDs[:at], append([]int{id}, p.itemIDs[at:]...)...)
+		p.calls = append(p.calls, fmt.Sprintf("move %d after %d", id, after))
+
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+// index returns the position of a playlist entry
+func (p *fakePlaylist) index(itemID int) int {
+	for i, id := range p.itemIDs {
+		if id == itemID {
+			return i
+		}
+	}
+	return -1
+}
+
+// items returns the playlist entries as the destination lists them
+func (p *fakePlaylist) items() []plex.PlaylistItem {
+	items := make([]plex.PlaylistItem, len(p.ratingKeys))
+	for i := range p.ratingKeys {
+		items[i] = plex.PlaylistItem{RatingKey: plex.FlexibleRatingKey{Value: p.ratingKeys[i]}, PlaylistItemID: plex.FlexibleInt{Value: p.itemIDs[i]}}
+	}
+	return items
+}
+
+func TestReconcilePlaylistItems(t *testing.T) {
+	tests := []struct {
+		name      string
+		dest      []string // Rating keys of the destination entries; entry i has playlist item ID i+1
+		wanted    []string
+		wantCalls []string
+	}{
+		{
+			name:   "in sync",
+			dest:   []string{"a", "b", "c"},
+			wanted: []string{"a", "b", "c"},
+		},
+		{
+			name:      "missing entries are appended",
+			dest:      []string{"a", "b"},
+			wanted:    []string{"a", "b", "c", "d"},
+			wantCalls: []string{"add c,d"},
+		},
+		{
+			name:      "entries no longer wanted are removed",
+			dest:      []string{"a", "x", "b", "y"},
+			wanted:    []string{"a", "b"},
+			wantCalls: []string{"remove 2", "remove 4"},
+		},
+		{
+			name:      "duplicates are removed",
+			dest:      []string{"a", "b", "a", "b"},
+			wanted:    []string{"a", "b"},
+			wantCalls: []string{"remove 3", "remove 4"},
+		},
+		{
+			name:      "reordered entries are moved",
+			dest:      []string{"c", "a", "b"},
+			wanted:    []string{"a", "b", "c"},
+			wantCalls: []string{"move 2 after 0", "move 3 after 2"},
+		},
+		{
+			name:      "added entries are moved into place",
+			dest:      []string{"b", "x", "b"},
+			wanted:    []string{"a", "b"},
+			wantCalls: []string{"remove 2", "remove 3", "add a", "move 4 after 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist := &fakePlaylist{ratingKeys: append([]string{}, tt.dest...), nextID: len(tt.dest)}
+			for i := range tt.dest {
+				playlist.itemIDs = append(playlist.itemIDs, i+1)
+			}
+			dest := newFakePlex(t, playlist.serve)
+			s := &Synchronizer{destClient: dest.client, logger: logger.New("error")}
+
+			if err := s.reconcilePlaylistItems("Favourites", "7", playlist.items(), tt.wanted); err != nil {
+				t.Fatalf("reconcilePlaylistItems() failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(playlist.ratingKeys, tt.wanted) {
+				t.Errorf("playlist = %v, want %v", playlist.ratingKeys, tt.wanted)
+			}
+			if !reflect.DeepEqual(playlist.calls, tt.wantCalls) {
+				t.Errorf("calls = %q, want %q", playlist.calls, tt.wantCalls)
+			}
+		})
+	}
+}
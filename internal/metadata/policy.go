@@ -0,0 +1,521 @@
+package metadata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/discovery"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
+)
+
+// baselineDocument is the state store document name for the last synced field values
+const baselineDocument = "metadata-baseline"
+
+// fieldBaseline remembers the value of every synced field after it was last reconciled, so a later
+// difference can be attributed to the server that actually changed instead of to stale data
+type fieldBaseline struct {
+	mu     sync.Mutex
+	Values map[string]string `json:"values"` // Keyed by baselineKey
+}
+
+// loadBaseline loads the persisted field baseline, starting empty when there is none
+func loadBaseline(store *state.Store, log *logger.Logger) *fieldBaseline {
+	baseline := &fieldBaseline{Values: make(map[string]string)}
+	if err := store.Load(baselineDocument, baseline); err != nil {
+		log.WithError(err).Warn("Failed to load metadata baseline, conflicts will be resolved by policy until fields are synced again")
+	}
+	if baseline.Values == nil {
+		baseline.Values = make(map[string]string)
+	}
+	return baseline
+}
+
+// get returns the last synced value of a field
+func (b *fieldBaseline) get(key string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	value, ok := b.Values[key]
+	return value, ok
+}
+
+// set records the value a field has on both servers after it was reconciled
+func (b *fieldBaseline) set(key, value string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Values[key] = value
+}
+
+// baselineKey identifies one field of one item pair; scope separates Plex Home users from the owner
+func baselineKey(scope, sourceRatingKey, destRatingKey, field string) string {
+	return scope + "|" + sourceRatingKey + "|" + destRatingKey + "|" + field
+}
+
+// syncAction says which servers receive the other side's value of a field
+type syncAction struct {
+	updateDest   bool
+	updateSource bool
+	merge        bool // Both sides receive the union of their tags
+}
+
+// decide applies a field group's policy to the changes seen since the last sync.
+// With no baseline both sides count as changed, so the conflict rule settles the first sync. In a one-way
+// direction a change on the receiving side conflicts with the sending side, which keeps its value unless the
+// conflict rule says otherwise.
+func (s *Synchronizer) decide(group string, sourceChanged, destChanged, sourceNewer bool) syncAction {
+	policy := s.policies[group]
+	if !sourceChanged && !destChanged {
+		return syncAction{}
+	}
+
+	switch policy.Direction {
+	case config.DirectionBidirectional:
+		switch {
+		case sourceChanged && !destChanged:
+			return syncAction{updateDest: true}
+		case destChanged && !sourceChanged:
+			return syncAction{updateSource: true}
+		}
+		switch policy.Conflict {
+		case config.ConflictUnion:
+			return syncAction{updateDest: true, updateSource: true, merge: true}
+		case config.ConflictSource:
+			return syncAction{updateDest: true}
+		default:
+			return syncAction{updateDest: sourceNewer, updateSource: !sourceNewer}
+		}
+
+	case config.DirectionSourceToDest:
+		if !destChanged {
+			return syncAction{updateDest: true}
+		}
+		switch policy.Conflict {
+		case config.ConflictUnion:
+			return syncAction{updateDest: true, merge: true}
+		case config.ConflictSource:
+			return syncAction{updateDest: true}
+		default:
+			return syncAction{updateDest: sourceNewer}
+		}
+
+	case config.DirectionDestToSource:
+		if !sourceChanged {
+			return syncAction{updateSource: true}
+		}
+		switch policy.Conflict {
+		case config.ConflictUnion:
+			return syncAction{updateSource: true, merge: true}
+		case config.ConflictSource:
+			return syncAction{}
+		default:
+			return syncAction{updateSource: !sourceNewer}
+		}
+	}
+
+	return syncAction{}
+}
+
+// reconcile compares a field's current values with its baseline and decides how to sync it.
+// Equal values are recorded as the new baseline and need no action.
+func (s *Synchronizer) reconcile(group, key, sourceValue, destValue string, sourceNewer bool) syncAction {
+	if s.policies[group].Direction == config.DirectionOff {
+		return syncAction{}
+	}
+	if sourceValue == destValue {
+		s.baseline.set(key, sourceValue)
+		return syncAction{}
+	}
+
+	base, known := s.baseline.get(key)
+	return s.decide(group, !known || sourceValue != base, !known || destValue != base, sourceNewer)
+}
+
+// itemFields holds the policy-synced fields of a movie, show or episode
+type itemFields struct {
+	ratingKey   string
+	libraryID   string
+	mediaType   string
+	title       string
+	summary     string
+	userRating  float64
+	labels      []string
+	genres      []string
+	collections []string
+	thumb       string // Selected artwork paths
+	art         string
+	theme       string
+	updatedAt   int
+	locked      map[string]bool // Fields locked on this server
+	editable    map[string]plex.FieldValue
+}
+
+// fieldsOf extracts the policy-synced fields from an enhanced item
+func fieldsOf(enhanced *discovery.EnhancedMediaItem) (itemFields, bool) {
+	fields := itemFields{libraryID: enhanced.LibraryID, mediaType: enhanced.ItemType}
+
+	switch v := enhanced.Item.(type) {
+	case plex.Movie:
+		fields.ratingKey, fields.title, fields.summary = v.RatingKey.String(), v.Title, v.Summary
+		fields.userRating, fields.updatedAt = v.UserRating.Value, v.UpdatedAt
+		fields.labels, fields.genres, fields.collections = labelTags(v.Label), genreTags(v.Genre), collectionTags(v.Collection)
+		fields.thumb, fields.art, fields.theme = v.Thumb, v.Art, v.Theme
+		fields.locked = lockedFields(v.Field)
+		fields.editable = plex.EditableFieldValues(v, plex.MovieFields)
+	case plex.TVShow:
+		fields.ratingKey, fields.title, fields.summary = v.RatingKey.String(), v.Title, v.Summary
+		fields.userRating, fields.updatedAt = v.UserRating.Value, v.UpdatedAt
+		fields.labels, fields.genres, fields.collections = labelTags(v.Label), genreTags(v.Genre), collectionTags(v.Collection)
+		fields.thumb, fields.art, fields.theme = v.Thumb, v.Art, v.Theme
+		fields.locked = lockedFields(v.Field)
+		fields.editable = plex.EditableFieldValues(v, plex.ShowFields)
+	case plex.Episode:
+		fields.ratingKey, fields.title, fields.summary = v.RatingKey.String(), v.Title, v.Summary
+		fields.userRating, fields.updatedAt = v.UserRating.Value, v.UpdatedAt
+		fields.labels, fields.genres, fields.collections = labelTags(v.Label), genreTags(v.Genre), collectionTags(v.Collection)
+		// Episode backgrounds and themes are inherited from the show, so only the thumbnail is the episode's own
+		fields.thumb = v.Thumb
+		fields.locked = lockedFields(v.Field)
+		fields.editable = plex.EditableFieldValues(v, plex.EpisodeFields)
+	default:
+		return itemFields{}, false
+	}
+
+	return fields, true
+}
+
+// syncFieldGroups reconciles the rating, tag, text and artwork field groups of an item pair according to their policies
+func (s *Synchronizer) syncFieldGroups(source, dest itemFields) []string {
+	var errors []string
+	sourceNewer := source.updatedAt > dest.updatedAt
+
+	if err := s.syncRating(source, dest, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("user rating: %v", err))
+	}
+	if err := s.syncTags(config.FieldGroupLabels, "label", source, dest, source.labels, dest.labels, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("labels: %v", err))
+	}
+	if err := s.syncTags(config.FieldGroupLabels, "genre", source, dest, source.genres, dest.genres, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("genres: %v", err))
+	}
+	// Collection membership is a tag too; tagging an item into a missing collection creates it
+	if err := s.syncTags(config.FieldGroupCollections, "collection", source, dest, source.collections, dest.collections, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("collections: %v", err))
+	}
+	if err := s.syncText(config.FieldGroupText, "title", source, dest, source.title, dest.title, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("title: %v", err))
+	}
+	if err := s.syncText(config.FieldGroupText, "summary", source, dest, source.summary, dest.summary, sourceNewer); err != nil {
+		errors = append(errors, fmt.Sprintf("summary: %v", err))
+	}
+	errors = append(errors, s.syncEditableFields(source, dest, sourceNewer)...)
+	errors = append(errors, s.syncArtwork(source, dest, sourceNewer)...)
+
+	return errors
+}
+
+// syncEditableFields reconciles the descriptor-driven fields (plex.MovieFields etc.) of an item pair:
+// single values under the text policy, credits under the credits policy and item settings under the settings policy
+func (s *Synchronizer) syncEditableFields(source, dest itemFields, sourceNewer bool) []string {
+	names := make([]string, 0, len(source.editable))
+	for name := range source.editable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errors []string
+	for _, name := range names {
+		sourceValue, destValue := source.editable[name], dest.editable[name]
+
+		var err error
+		switch sourceValue.Kind {
+		case plex.FieldText:
+			err = s.syncText(config.FieldGroupText, name, source, dest, sourceValue.Text, destValue.Text, sourceNewer)
+		case plex.FieldTags:
+			err = s.syncTags(config.FieldGroupCredits, name, source, dest, sourceValue.Tags, destValue.Tags, sourceNewer)
+		case plex.FieldPref:
+			err = s.syncPref(config.FieldGroupSettings, name, source, dest, sourceValue.Text, destValue.Text, sourceNewer)
+		}
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return errors
+}
+
+// syncRating reconciles the user rating; a missing (zero) rating is never pushed, since it cannot be cleared reliably
+func (s *Synchronizer) syncRating(source, dest itemFields, sourceNewer bool) error {
+	key := baselineKey("", source.ratingKey, dest.ratingKey, "rating")
+	sourceValue, destValue := fmt.Sprintf("%.1f", source.userRating), fmt.Sprintf("%.1f", dest.userRating)
+
+	action := s.reconcile(config.FieldGroupRating, key, sourceValue, destValue, sourceNewer)
+	switch {
+	case action.updateDest && source.userRating > 0:
+		if err := s.destClient.SetUserRating(dest.ratingKey, source.userRating); err != nil {
+			return err
+		}
+		s.baseline.set(key, sourceValue)
+		s.logFieldSync("rating", "source_to_dest", source, dest, sourceValue)
+	case action.updateSource && dest.userRating > 0:
+		if err := s.sourceClient.SetUserRating(source.ratingKey, dest.userRating); err != nil {
+			return err
+		}
+		s.baseline.set(key, destValue)
+		s.logFieldSync("rating", "dest_to_source", source, dest, destValue)
+	}
+	return nil
+}
+
+// syncText reconciles a single-value field (e.g. "title", "summary" or "studio") under the given group's policy
+func (s *Synchronizer) syncText(group, field string, source, dest itemFields, sourceValue, destValue string, sourceNewer bool) error {
+	key := baselineKey("", source.ratingKey, dest.ratingKey, field)
+
+	setText := func(client *plex.Client, item itemFields, value string, lock bool) error {
+		return client.SetTextField(item.ratingKey, item.libraryID, field, value, item.mediaType, lock)
+	}
+
+	action := s.reconcile(group, key, sourceValue, destValue, sourceNewer)
+	switch {
+	case action.updateDest && (sourceValue != "" || field != "title"):
+		if err := setText(s.destClient, dest, sourceValue, s.shouldLock(field, source)); err != nil {
+			return err
+		}
+		s.baseline.set(key, sourceValue)
+		s.logFieldSync(field, "source_to_dest", source, dest, sourceValue)
+	case action.updateSource && (destValue != "" || field != "title"):
+		if err := setText(s.sourceClient, source, destValue, s.shouldLock(field, dest)); err != nil {
+			return err
+		}
+		s.baseline.set(key, destValue)
+		s.logFieldSync(field, "dest_to_source", source, dest, destValue)
+	}
+	return nil
+}
+
+// syncTags reconciles a tag list field (e.g. "label", "collection" or "director") under the given group's policy.
+// A one-way union policy only ever adds tags to the receiving side; bidirectional union merges both sides
+// when both changed.
+func (s *Synchronizer) syncTags(group, field string, source, dest itemFields, sourceTags, destTags []string, sourceNewer bool) error {
+	policy := s.policies[group]
+	if policy.Direction == config.DirectionOff {
+		return nil
+	}
+
+	key := baselineKey("", source.ratingKey, dest.ratingKey, field)
+	sourceValue, destValue := joinTags(sourceTags), joinTags(destTags)
+	// Tags only the receiving side has that SyncArr never synced were added there by hand. They are kept,
+	// and in a one-way direction they are no change to sync back either.
+	synced := s.baselineTags(key)
+
+	var action syncAction
+	switch {
+	case policy.Conflict == config.ConflictUnion && policy.Direction != config.DirectionBidirectional:
+		action = s.decide(group, true, true, sourceNewer)
+	case policy.Direction == config.DirectionSourceToDest:
+		action = s.reconcile(group, key, sourceValue, joinTags(withoutLocalTags(destTags, sourceTags, synced)), sourceNewer)
+	case policy.Direction == config.DirectionDestToSource:
+		action = s.reconcile(group, key, joinTags(withoutLocalTags(sourceTags, destTags, synced)), destValue, sourceNewer)
+	default:
+		action = s.reconcile(group, key, sourceValue, destValue, sourceNewer)
+	}
+
+	sourceTarget, destTarget := keepUnsyncedTags(destTags, sourceTags, synced), keepUnsyncedTags(sourceTags, destTags, synced)
+	if action.merge {
+		union := unionTags(sourceTags, destTags)
+		sourceTarget, destTarget = union, union
+	}
+
+	if action.updateDest && joinTags(destTarget) != destValue {
+		if err := s.applyTags(s.destClient, dest, field, destTags, destTarget, s.shouldLock(field, source)); err != nil {
+			return fmt.Errorf("destination: %w", err)
+		}
+		s.logFieldSync(field, "source_to_dest", source, dest, destTarget)
+	}
+	if action.updateSource && joinTags(sourceTarget) != sourceValue {
+		if err := s.applyTags(s.sourceClient, source, field, sourceTags, sourceTarget, s.shouldLock(field, dest)); err != nil {
+			return fmt.Errorf("source: %w", err)
+		}
+		s.logFieldSync(field, "dest_to_source", source, dest, sourceTarget)
+	}
+
+	// Record the value both servers now share; a one-way union leaves them different on purpose
+	switch {
+	case action.merge && action.updateDest && action.updateSource:
+		s.baseline.set(key, joinTags(destTarget))
+	case action.merge:
+	case action.updateDest:
+		s.baseline.set(key, sourceValue)
+	case action.updateSource:
+		s.baseline.set(key, destValue)
+	}
+	return nil
+}
+
+// applyTags makes an item's tags equal to target, adding the missing ones and removing the rest.
+// The sync label is never removed, since that would stop the item from syncing.
+func (s *Synchronizer) applyTags(client *plex.Client, item itemFields, field string, current, target []string, lock bool) error {
+	if len(target) > 0 {
+		if err := client.UpdateMediaField(item.ratingKey, item.libraryID, target, field, lock, item.mediaType); err != nil {
+			return err
+		}
+	}
+
+	keep := make(map[string]bool, len(target))
+	for _, tag := range target {
+		keep[strings.ToLower(tag)] = true
+	}
+	var remove []string
+	for _, tag := range current {
+		if !keep[strings.ToLower(tag)] && !(field == "label" && strings.EqualFold(tag, s.syncLabel)) {
+			remove = append(remove, tag)
+		}
+	}
+	if len(remove) > 0 {
+		if err := client.RemoveMediaFieldKeywords(item.ratingKey, item.libraryID, remove, field, lock, item.mediaType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncPref reconciles a per-item setting under the given group's policy. Settings at their library default
+// read as empty and are never pushed, since the default value differs per setting.
+func (s *Synchronizer) syncPref(group, field string, source, dest itemFields, sourceValue, destValue string, sourceNewer bool) error {
+	key := baselineKey("", source.ratingKey, dest.ratingKey, field)
+
+	action := s.reconcile(group, key, sourceValue, destValue, sourceNewer)
+	switch {
+	case action.updateDest && sourceValue != "":
+		if err := s.destClient.SetPref(dest.ratingKey, field, sourceValue); err != nil {
+			return err
+		}
+		s.baseline.set(key, sourceValue)
+		s.logFieldSync(field, "source_to_dest", source, dest, sourceValue)
+	case action.updateSource && destValue != "":
+		if err := s.sourceClient.SetPref(source.ratingKey, field, destValue); err != nil {
+			return err
+		}
+		s.baseline.set(key, destValue)
+		s.logFieldSync(field, "dest_to_source", source, dest, destValue)
+	}
+	return nil
+}
+
+// shouldLock reports whether a field written from the sending item gets locked on the receiving item:
+// when it is configured to be locked, or when it is locked on the sending item
+func (s *Synchronizer) shouldLock(field string, from itemFields) bool {
+	return s.lockFields[field] || from.locked[field]
+}
+
+// lockedFields returns the names of the locked fields of an item
+func lockedFields(fields []plex.Field) map[string]bool {
+	locked := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field.Locked.Value {
+			locked[field.Name] = true
+		}
+	}
+	return locked
+}
+
+// logFieldSync logs one field value copied between the servers
+func (s *Synchronizer) logFieldSync(field, direction string, source, dest itemFields, value interface{}) {
+	s.logger.WithFields(map[string]interface{}{
+		"field":             field,
+		"direction":         direction,
+		"source_rating_key": source.ratingKey,
+		"dest_rating_key":   dest.ratingKey,
+		"value":             value,
+	}).Debug("Synced metadata field")
+}
+
+// labelTags returns the tag strings of labels
+func labelTags(labels []plex.Label) []string {
+	tags := make([]string, 0, len(labels))
+	for _, label := range labels {
+		tags = append(tags, label.Tag)
+	}
+	return tags
+}
+
+// genreTags returns the tag strings of genres
+func genreTags(genres []plex.Genre) []string {
+	tags := make([]string, 0, len(genres))
+	for _, genre := range genres {
+		tags = append(tags, genre.Tag)
+	}
+	return tags
+}
+
+// collectionTags returns the tag strings of collections
+func collectionTags(collections []plex.Collection) []string {
+	tags := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		tags = append(tags, collection.Tag)
+	}
+	return tags
+}
+
+// joinTags returns a canonical, order-independent representation of a tag list
+func joinTags(tags []string) string {
+	normalized := make([]string, len(tags))
+	for i, tag := range tags {
+		normalized[i] = strings.ToLower(tag)
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, "\n")
+}
+
+// baselineTags returns the lowercased tags last synced for a tag field, or none when it was never synced
+func (s *Synchronizer) baselineTags(key string) map[string]bool {
+	synced := make(map[string]bool)
+	if base, known := s.baseline.get(key); known && base != "" {
+		for _, tag := range strings.Split(base, "\n") {
+			synced[tag] = true
+		}
+	}
+	return synced
+}
+
+// keepUnsyncedTags returns the incoming tags plus the receiving side's tags that were never synced
+func keepUnsyncedTags(incoming, current []string, synced map[string]bool) []string {
+	var local []string
+	for _, tag := range current {
+		if !synced[strings.ToLower(tag)] {
+			local = append(local, tag)
+		}
+	}
+	return unionTags(incoming, local)
+}
+
+// withoutLocalTags returns the current tags without those added by hand: tags that were never synced
+// and that the other side doesn't have
+func withoutLocalTags(current, other []string, synced map[string]bool) []string {
+	otherSet := make(map[string]bool, len(other))
+	for _, tag := range other {
+		otherSet[strings.ToLower(tag)] = true
+	}
+	var kept []string
+	for _, tag := range current {
+		if synced[strings.ToLower(tag)] || otherSet[strings.ToLower(tag)] {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// unionTags merges two tag lists, keeping the first spelling of tags that differ only in case
+func unionTags(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var union []string
+	for _, tag := range append(append([]string{}, a...), b...) {
+		if !seen[strings.ToLower(tag)] {
+			seen[strings.ToLower(tag)] = true
+			union = append(union, tag)
+		}
+	}
+	return union
+}
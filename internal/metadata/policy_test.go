@@ -0,0 +1,332 @@
+package metadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// fakePlex is a stand-in Plex server that records the requests it receives and answers them with handler
+type fakePlex struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	client   *plex.Client
+}
+
+// newFakePlex starts a fake Plex server and connects a client to it. The /identity endpoint is answered
+// by the fake itself; handler answers every other request and may be nil to reply with an empty 200.
+func newFakePlex(t *testing.T, handler http.HandlerFunc) *fakePlex {
+	t.Helper()
+	fake := &fakePlex{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"MediaContainer":{"machineIdentifier":"fake-machine"}}`))
+			return
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, r)
+		fake.mu.Unlock()
+		if handler != nil {
+			handler(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse fake server URL: %v", err)
+	}
+	client, err := plex.NewClient(&config.PlexServerConfig{
+		Host:  serverURL.Hostname(),
+		Port:  serverURL.Port(),
+		Token: "test-token",
+	}, logger.New("error"))
+	if err != nil {
+		t.Fatalf("failed to connect to fake server: %v", err)
+	}
+	fake.client = client
+	return fake
+}
+
+// tagIndexParam matches the query parameters that set a tag, e.g. "label[0].tag.tag"
+var tagIndexParam = regexp.MustCompile(`^[a-z]+\[\d+\]\.tag\.tag$`)
+
+// tagEdits returns the sorted tags the recorded requests set and removed
+func (f *fakePlex) tagEdits() (set, removed []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.requests {
+		if r.Method != http.MethodPut {
+			continue
+		}
+		for param, values := range r.URL.Query() {
+			switch {
+			case tagIndexParam.MatchString(param):
+				set = append(set, values...)
+			case strings.HasSuffix(param, "[].tag.tag-"):
+				removed = append(removed, strings.Split(values[0], ",")...)
+			}
+		}
+	}
+	sort.Strings(set)
+	sort.Strings(removed)
+	return set, removed
+}
+
+func TestDecide(t *testing.T) {
+	tests := []struct {
+		direction     string
+		conflict      string
+		sourceChanged bool
+		destChanged   bool
+		sourceNewer   syncAction // Wanted action when the source was updated more recently
+		destNewer     syncAction // Wanted action when the destination was updated more recently
+	}{
+		{config.DirectionBidirectional, config.ConflictNewest, false, false, syncAction{}, syncAction{}},
+		{config.DirectionBidirectional, config.ConflictNewest, true, false, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionBidirectional, config.ConflictNewest, false, true, syncAction{updateSource: true}, syncAction{updateSource: true}},
+		{config.DirectionBidirectional, config.ConflictNewest, true, true, syncAction{updateDest: true}, syncAction{updateSource: true}},
+		{config.DirectionBidirectional, config.ConflictSource, false, false, syncAction{}, syncAction{}},
+		{config.DirectionBidirectional, config.ConflictSource, true, false, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionBidirectional, config.ConflictSource, false, true, syncAction{updateSource: true}, syncAction{updateSource: true}},
+		{config.DirectionBidirectional, config.ConflictSource, true, true, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionBidirectional, config.ConflictUnion, false, false, syncAction{}, syncAction{}},
+		{config.DirectionBidirectional, config.ConflictUnion, true, false, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionBidirectional, config.ConflictUnion, false, true, syncAction{updateSource: true}, syncAction{updateSource: true}},
+		{config.DirectionBidirectional, config.ConflictUnion, true, true, syncAction{updateDest: true, updateSource: true, merge: true}, syncAction{updateDest: true, updateSource: true, merge: true}},
+		{config.DirectionSourceToDest, config.ConflictNewest, false, false, syncAction{}, syncAction{}},
+		{config.DirectionSourceToDest, config.ConflictNewest, true, false, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionSourceToDest, config.ConflictNewest, false, true, syncAction{updateDest: true}, syncAction{}},
+		{config.DirectionSourceToDest, config.ConflictNewest, true, true, syncAction{updateDest: true}, syncAction{}},
+		{config.DirectionSourceToDest, config.ConflictSource, false, false, syncAction{}, syncAction{}},
+		{config.DirectionSourceToDest, config.ConflictSource, true, false, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionSourceToDest, config.ConflictSource, false, true, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionSourceToDest, config.ConflictSource, true, true, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionSourceToDest, config.ConflictUnion, false, false, syncAction{}, syncAction{}},
+		{config.DirectionSourceToDest, config.ConflictUnion, true, false, syncAction{updateDest: true}, syncAction{updateDest: true}},
+		{config.DirectionSourceToDest, config.ConflictUnion, false, true, syncAction{updateDest: true, merge: true}, syncAction{updateDest: true, merge: true}},
+		{config.DirectionSourceToDest, config.ConflictUnion, true, true, syncAction{updateDest: true, merge: true}, syncAction{updateDest: true, merge: true}},
+		{config.DirectionDestToSource, config.ConflictNewest, false, false, syncAction{}, syncAction{}},
+		{config.DirectionDestToSource, config.ConflictNewest, true, false, syncAction{}, syncAction{updateSource: true}},
+		{config.DirectionDestToSource, config.ConflictNewest, false, true, syncAction{updateSource: true}, syncAction{updateSource: true}},
+		{config.DirectionDestToSource, config.ConflictNewest, true, true, syncAction{}, syncAction{updateSource: true}},
+		{config.DirectionDestToSource, config.ConflictSource, false, false, syncAction{}, syncAction{}},
+		{config.DirectionDestToSource, config.ConflictSource, true, false, syncAction{}, syncAction{}},
+		{config.DirectionDestToSource, config.ConflictSource, false, true, syncAction{updateSource: true}, syncAction{updateSource: true}},
+		{config.DirectionDestToSource, config.ConflictSource, true, true, syncAction{}, syncAction{}},
+		{config.DirectionDestToSource, config.ConflictUnion, false, false, syncAction{}, syncAction{}},
+		{config.DirectionDestToSource, config.ConflictUnion, true, false, syncAction{updateSource: true, merge: true}, syncAction{updateSource: true, merge: true}},
+		{config.DirectionDestToSource, config.ConflictUnion, false, true, syncAction{updateSource: true}, syncAction{updateSource: true}},
+		{config.DirectionDestToSource, config.ConflictUnion, true, true, syncAction{updateSource: true, merge: true}, syncAction{updateSource: true, merge: true}},
+		{config.DirectionOff, config.ConflictNewest, false, false, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictNewest, true, false, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictNewest, false, true, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictNewest, true, true, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictSource, false, false, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictSource, true, false, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictSource, false, true, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictSource, true, true, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictUnion, false, false, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictUnion, true, false, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictUnion, false, true, syncAction{}, syncAction{}},
+		{config.DirectionOff, config.ConflictUnion, true, true, syncAction{}, syncAction{}},
+	}
+
+	for _, tt := range tests {
+		s := &Synchronizer{policies: map[string]config.SyncPolicy{
+			config.FieldGroupLabels: {Direction: tt.direction, Conflict: tt.conflict},
+		}}
+		for _, sourceNewer := range []bool{true, false} {
+			want := tt.destNewer
+			if sourceNewer {
+				want = tt.sourceNewer
+			}
+			got := s.decide(config.FieldGroupLabels, tt.sourceChanged, tt.destChanged, sourceNewer)
+			if got != want {
+				t.Errorf("decide(%s:%s, sourceChanged=%t, destChanged=%t, sourceNewer=%t) = %+v, want %+v",
+					tt.direction, tt.conflict, tt.sourceChanged, tt.destChanged, sourceNewer, got, want)
+			}
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	const key = "|1|2|title"
+
+	tests := []struct {
+		name         string
+		policy       config.SyncPolicy
+		baseline     map[string]string
+		source       string
+		dest         string
+		want         syncAction
+		wantBaseline map[string]string
+	}{
+		{
+			name:         "equal values become the baseline",
+			policy:       config.SyncPolicy{Direction: config.DirectionBidirectional, Conflict: config.ConflictNewest},
+			baseline:     map[string]string{},
+			source:       "Alien",
+			dest:         "Alien",
+			want:         syncAction{},
+			wantBaseline: map[string]string{key: "Alien"},
+		},
+		{
+			name:         "first sync lets the conflict rule decide",
+			policy:       config.SyncPolicy{Direction: config.DirectionDestToSource, Conflict: config.ConflictSource},
+			baseline:     map[string]string{},
+			source:       "Alien",
+			dest:         "Aliens",
+			want:         syncAction{},
+			wantBaseline: map[string]string{},
+		},
+		{
+			name:         "source change is sent to the destination",
+			policy:       config.SyncPolicy{Direction: config.DirectionBidirectional, Conflict: config.ConflictNewest},
+			baseline:     map[string]string{key: "Alien"},
+			source:       "Alien (Director's Cut)",
+			dest:         "Alien",
+			want:         syncAction{updateDest: true},
+			wantBaseline: map[string]string{key: "Alien"},
+		},
+		{
+			name:         "destination change is sent to the source",
+			policy:       config.SyncPolicy{Direction: config.DirectionBidirectional, Conflict: config.ConflictNewest},
+			baseline:     map[string]string{key: "Alien"},
+			source:       "Alien",
+			dest:         "Alien (Director's Cut)",
+			want:         syncAction{updateSource: true},
+			wantBaseline: map[string]string{key: "Alien"},
+		},
+		{
+			name:         "off leaves the baseline alone",
+			policy:       config.SyncPolicy{Direction: config.DirectionOff, Conflict: config.ConflictSource},
+			baseline:     map[string]string{},
+			source:       "Alien",
+			dest:         "Alien",
+			want:         syncAction{},
+			wantBaseline: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Synchronizer{
+				policies: map[string]config.SyncPolicy{config.FieldGroupText: tt.policy},
+				baseline: &fieldBaseline{Values: tt.baseline},
+			}
+			// The destination is newer, so only a source-wins conflict rule sends the source value
+			if got := s.reconcile(config.FieldGroupText, key, tt.source, tt.dest, false); got != tt.want {
+				t.Errorf("reconcile() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(s.baseline.Values, tt.wantBaseline) {
+				t.Errorf("baseline = %v, want %v", s.baseline.Values, tt.wantBaseline)
+			}
+		})
+	}
+}
+
+func TestSyncTags(t *testing.T) {
+	key := baselineKey("", "1", "2", "label")
+
+	tests := []struct {
+		name            string
+		policy          config.SyncPolicy
+		baseline        map[string]string
+		sourceTags      []string
+		destTags        []string
+		wantSourceSet   []string
+		wantDestSet     []string
+		wantDestRemoved []string
+		wantBaseline    map[string]string
+	}{
+		{
+			name:         "first sync keeps tags added by hand on the destination",
+			policy:       config.SyncPolicy{Direction: config.DirectionSourceToDest, Conflict: config.ConflictSource},
+			baseline:     map[string]string{},
+			sourceTags:   []string{"Action", "Drama"},
+			destTags:     []string{"Action", "Favourite"},
+			wantDestSet:  []string{"Action", "Drama", "Favourite"},
+			wantBaseline: map[string]string{key: "action\ndrama"},
+		},
+		{
+			name:         "tags added by hand are no change in a one-way direction",
+			policy:       config.SyncPolicy{Direction: config.DirectionSourceToDest, Conflict: config.ConflictSource},
+			baseline:     map[string]string{key: "action\ndrama"},
+			sourceTags:   []string{"Action", "Drama"},
+			destTags:     []string{"Action", "Drama", "Favourite"},
+			wantBaseline: map[string]string{key: "action\ndrama"},
+		},
+		{
+			name:            "only previously synced tags are removed",
+			policy:          config.SyncPolicy{Direction: config.DirectionSourceToDest, Conflict: config.ConflictSource},
+			baseline:        map[string]string{key: "action\ndrama"},
+			sourceTags:      []string{"Action"},
+			destTags:        []string{"Action", "Drama", "Favourite"},
+			wantDestSet:     []string{"Action", "Favourite"},
+			wantDestRemoved: []string{"Drama"},
+			wantBaseline:    map[string]string{key: "action"},
+		},
+		{
+			name:         "one-way union only adds and keeps the baseline",
+			policy:       config.SyncPolicy{Direction: config.DirectionSourceToDest, Conflict: config.ConflictUnion},
+			baseline:     map[string]string{key: "action"},
+			sourceTags:   []string{"Drama"},
+			destTags:     []string{"Action"},
+			wantDestSet:  []string{"Action", "Drama"},
+			wantBaseline: map[string]string{key: "action"},
+		},
+		{
+			name:          "bidirectional union merges both changes",
+			policy:        config.SyncPolicy{Direction: config.DirectionBidirectional, Conflict: config.ConflictUnion},
+			baseline:      map[string]string{key: "action"},
+			sourceTags:    []string{"Action", "Drama"},
+			destTags:      []string{"Action", "Comedy"},
+			wantSourceSet: []string{"Action", "Comedy", "Drama"},
+			wantDestSet:   []string{"Action", "Comedy", "Drama"},
+			wantBaseline:  map[string]string{key: "action\ncomedy\ndrama"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, dest := newFakePlex(t, nil), newFakePlex(t, nil)
+			s := &Synchronizer{
+				sourceClient: source.client,
+				destClient:   dest.client,
+				policies:     map[string]config.SyncPolicy{config.FieldGroupLabels: tt.policy},
+				baseline:     &fieldBaseline{Values: tt.baseline},
+				logger:       logger.New("error"),
+			}
+			sourceItem := itemFields{ratingKey: "1", libraryID: "1", mediaType: "movie", updatedAt: 200}
+			destItem := itemFields{ratingKey: "2", libraryID: "1", mediaType: "movie", updatedAt: 100}
+
+			if err := s.syncTags(config.FieldGroupLabels, "label", sourceItem, destItem, tt.sourceTags, tt.destTags, true); err != nil {
+				t.Fatalf("syncTags() failed: %v", err)
+			}
+
+			sourceSet, sourceRemoved := source.tagEdits()
+			destSet, destRemoved := dest.tagEdits()
+			if !reflect.DeepEqual(sourceSet, tt.wantSourceSet) || len(sourceRemoved) > 0 {
+				t.Errorf("source set %v and removed %v, want set %v", sourceSet, sourceRemoved, tt.wantSourceSet)
+			}
+			if !reflect.DeepEqual(destSet, tt.wantDestSet) || !reflect.DeepEqual(destRemoved, tt.wantDestRemoved) {
+				t.Errorf("destination set %v and removed %v, want set %v and removed %v", destSet, destRemoved, tt.wantDestSet, tt.wantDestRemoved)
+			}
+			if !reflect.DeepEqual(s.baseline.Values, tt.wantBaseline) {
+				t.Errorf("baseline = %v, want %v", s.baseline.Values, tt.wantBaseline)
+			}
+		})
+	}
+}
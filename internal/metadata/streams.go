@@ -0,0 +1,176 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// syncStreamSelection copies the selected audio and subtitle streams of each media part to the matching part on the
+// other server as the streams policy decides. Parts are paired by position; streams are matched by language,
+// codec, index and title, since stream IDs differ between servers.
+func (s *Synchronizer) syncStreamSelection(scope string, sourceClient, destClient *plex.Client, sourceRatingKey, destRatingKey string, sourceState, destState *plex.WatchedState) error {
+	if s.policies[config.FieldGroupStreams].Direction == config.DirectionOff {
+		return nil
+	}
+
+	sourceParts, destParts := mediaParts(sourceState.Media), mediaParts(destState.Media)
+	sourceNewer := sourceState.LastViewedAt > destState.LastViewedAt
+
+	for i := 0; i < len(sourceParts) && i < len(destParts); i++ {
+		sourcePart, destPart := sourceParts[i], destParts[i]
+		if sourcePart.ID == 0 || destPart.ID == 0 {
+			continue
+		}
+		sourceAudio, sourceSubtitle := selectedStream(sourcePart, plex.StreamTypeAudio), selectedStream(sourcePart, plex.StreamTypeSubtitle)
+		destAudio, destSubtitle := selectedStream(destPart, plex.StreamTypeAudio), selectedStream(destPart, plex.StreamTypeSubtitle)
+
+		key := baselineKey(scope, sourceRatingKey, destRatingKey, fmt.Sprintf("streams:%d", i))
+		sourceValue := selectionSignature(sourceAudio, sourceSubtitle)
+		destValue := selectionSignature(destAudio, destSubtitle)
+		// Streams matched by weaker criteria have different signatures but are the same selection
+		if sameStream(matchStream(destPart, sourceAudio), destAudio) && sameStream(matchStream(destPart, sourceSubtitle), destSubtitle) {
+			destValue = sourceValue
+		}
+
+		action := s.reconcile(config.FieldGroupStreams, key, sourceValue, destValue, sourceNewer)
+		switch {
+		case action.updateDest:
+			applied, err := s.applyStreamSelection(destClient, destPart, sourceAudio, sourceSubtitle)
+			if err != nil {
+				return fmt.Errorf("failed to sync stream selection to destination: %w", err)
+			}
+			if applied {
+				s.baseline.set(key, sourceValue)
+				s.logStreamSync("source_to_dest", sourceRatingKey, destRatingKey, sourceValue)
+			}
+		case action.updateSource:
+			applied, err := s.applyStreamSelection(sourceClient, sourcePart, destAudio, destSubtitle)
+			if err != nil {
+				return fmt.Errorf("failed to sync stream selection to source: %w", err)
+			}
+			if applied {
+				s.baseline.set(key, destValue)
+				s.logStreamSync("dest_to_source", sourceRatingKey, destRatingKey, destValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyStreamSelection selects the streams of part that match the other server's selected audio and subtitle
+// streams; no selected subtitle turns subtitles off. Nothing is applied, and false is returned, when a selected
+// stream has no match on part, since the selection cannot be reproduced there.
+func (s *Synchronizer) applyStreamSelection(client *plex.Client, part plex.Part, audio, subtitle *plex.Stream) (bool, error) {
+	audioID, subtitleID := -1, 0
+	if audio != nil {
+		match := matchStream(part, audio)
+		if match == nil {
+			s.logUnmatchedStream(part, audio)
+			return false, nil
+		}
+		audioID = match.ID
+	}
+	if subtitle != nil {
+		match := matchStream(part, subtitle)
+		if match == nil {
+			s.logUnmatchedStream(part, subtitle)
+			return false, nil
+		}
+		subtitleID = match.ID
+	}
+	return true, client.SetStreamSelection(part.ID, audioID, subtitleID)
+}
+
+// logUnmatchedStream logs a selected stream that has no counterpart on the other server's part
+func (s *Synchronizer) logUnmatchedStream(part plex.Part, stream *plex.Stream) {
+	s.logger.WithFields(map[string]interface{}{
+		"part_id":     part.ID,
+		"stream_type": stream.StreamType,
+		"language":    stream.LanguageCode,
+		"codec":       stream.Codec,
+	}).Debug("No matching stream to select, leaving stream selection alone")
+}
+
+// logStreamSync logs a stream selection copied between the servers
+func (s *Synchronizer) logStreamSync(direction, sourceRatingKey, destRatingKey, selection string) {
+	s.logger.WithFields(map[string]interface{}{
+		"direction":         direction,
+		"source_rating_key": sourceRatingKey,
+		"dest_rating_key":   destRatingKey,
+		"selection":         selection,
+	}).Debug("Synced stream selection")
+}
+
+// mediaParts returns the parts of all media versions in order
+func mediaParts(media []plex.Media) []plex.Part {
+	var parts []plex.Part
+	for _, m := range media {
+		parts = append(parts, m.Part...)
+	}
+	return parts
+}
+
+// selectedStream returns the selected stream of a type, or nil when none is selected
+func selectedStream(part plex.Part, streamType int) *plex.Stream {
+	for i := range part.Stream {
+		if part.Stream[i].StreamType == streamType && part.Stream[i].Selected.Value {
+			return &part.Stream[i]
+		}
+	}
+	return nil
+}
+
+// matchStream finds the stream of part that best matches a stream of the other server. The language must match;
+// codec, index and title each make a candidate a better match.
+func matchStream(part plex.Part, want *plex.Stream) *plex.Stream {
+	if want == nil {
+		return nil
+	}
+
+	var best *plex.Stream
+	bestScore := -1
+	for i := range part.Stream {
+		candidate := &part.Stream[i]
+		if candidate.StreamType != want.StreamType || !strings.EqualFold(candidate.LanguageCode, want.LanguageCode) {
+			continue
+		}
+
+		score := 0
+		if strings.EqualFold(candidate.Codec, want.Codec) {
+			score += 4
+		}
+		if candidate.Index == want.Index {
+			score += 2
+		}
+		if candidate.Title == want.Title {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// sameStream reports whether two streams of one part are the same stream (or both absent)
+func sameStream(a, b *plex.Stream) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID == b.ID
+}
+
+// selectionSignature describes a selected audio and subtitle stream by their server-independent attributes
+func selectionSignature(audio, subtitle *plex.Stream) string {
+	describe := func(stream *plex.Stream) string {
+		if stream == nil {
+			return "none"
+		}
+		return fmt.Sprintf("%s/%s/%d/%s", strings.ToLower(stream.LanguageCode), strings.ToLower(stream.Codec), stream.Index, stream.Title)
+	}
+	return "audio=" + describe(audio) + ";subtitle=" + describe(subtitle)
+}
@@ -0,0 +1,81 @@
+package metadata
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// watchedStateWithStreams builds a watched state with one part holding the given streams
+func watchedStateWithStreams(partID int, streams ...plex.Stream) *plex.WatchedState {
+	return &plex.WatchedState{Media: []plex.Media{{Part: []plex.Part{{ID: partID, Stream: streams}}}}}
+}
+
+func TestSyncStreamSelection(t *testing.T) {
+	english := plex.Stream{ID: 11, StreamType: plex.StreamTypeAudio, Index: 1, Codec: "eac3", LanguageCode: "eng", Selected: plex.FlexibleBool{Value: true}}
+	german := plex.Stream{ID: 12, StreamType: plex.StreamTypeAudio, Index: 2, Codec: "eac3", LanguageCode: "ger"}
+
+	tests := []struct {
+		name         string
+		dest         *plex.WatchedState
+		wantPuts     int
+		wantBaseline bool
+	}{
+		{
+			name: "matching stream is selected",
+			dest: watchedStateWithStreams(2,
+				plex.Stream{ID: 21, StreamType: plex.StreamTypeAudio, Index: 1, Codec: "eac3", LanguageCode: "eng"},
+				plex.Stream{ID: 22, StreamType: plex.StreamTypeAudio, Index: 2, Codec: "eac3", LanguageCode: "ger", Selected: plex.FlexibleBool{Value: true}},
+			),
+			wantPuts:     2, // The fake does not change the selection, so the second cycle applies it again
+			wantBaseline: true,
+		},
+		{
+			name: "selection without a matching stream is left alone",
+			dest: watchedStateWithStreams(2,
+				plex.Stream{ID: 22, StreamType: plex.StreamTypeAudio, Index: 1, Codec: "eac3", LanguageCode: "ger", Selected: plex.FlexibleBool{Value: true}},
+			),
+			wantPuts:     0,
+			wantBaseline: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, dest := newFakePlex(t, nil), newFakePlex(t, nil)
+			s := &Synchronizer{
+				policies: map[string]config.SyncPolicy{
+					config.FieldGroupStreams: {Direction: config.DirectionSourceToDest, Conflict: config.ConflictSource},
+				},
+				baseline: &fieldBaseline{Values: map[string]string{}},
+				logger:   logger.New("error"),
+			}
+
+			// Run twice: an unmatched selection must not be retried as a request every cycle
+			for cycle := 0; cycle < 2; cycle++ {
+				if err := s.syncStreamSelection("", source.client, dest.client, "1", "2", watchedStateWithStreams(1, english, german), tt.dest); err != nil {
+					t.Fatalf("syncStreamSelection() failed: %v", err)
+				}
+			}
+
+			puts := 0
+			for _, r := range dest.requests {
+				if r.Method == http.MethodPut {
+					puts++
+				}
+			}
+			if puts != tt.wantPuts {
+				t.Errorf("destination received %d stream selection requests, want %d", puts, tt.wantPuts)
+			}
+			if _, known := s.baseline.get(baselineKey("", "1", "2", "streams:0")); known != tt.wantBaseline {
+				t.Errorf("baseline recorded = %t, want %t", known, tt.wantBaseline)
+			}
+			if len(source.requests) > 0 {
+				t.Errorf("source received %d requests, want none", len(source.requests))
+			}
+		})
+	}
+}
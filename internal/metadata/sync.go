@@ -2,29 +2,69 @@ package metadata
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/nullable-eth/syncarr/internal/config"
 	"github.com/nullable-eth/syncarr/internal/discovery"
 	"github.com/nullable-eth/syncarr/internal/logger"
 	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
 )
 
 // Synchronizer handles metadata synchronization between source and destination Plex servers
 type Synchronizer struct {
-	sourceClient *plex.Client
-	destClient   *plex.Client
-	logger       *logger.Logger
+	sourceClient     *plex.Client
+	destClient       *plex.Client
+	progressMinDelta time.Duration // Resume positions closer than this are left alone
+	policies         map[string]config.SyncPolicy
+	syncLabel        string
+	playlists        map[string]bool // Lowercased titles of source playlists to sync
+	lockFields       map[string]bool // Fields locked whenever they are written
+	syncMarkers      bool
+	store            *state.Store
+	baseline         *fieldBaseline
+	artwork          *artworkCache
+	logger           *logger.Logger
 }
 
-// NewSynchronizer creates a new metadata synchronizer
-func NewSynchronizer(sourceClient, destClient *plex.Client, logger *logger.Logger) *Synchronizer {
+// NewSynchronizer creates a new metadata synchronizer and loads the last synced field values
+func NewSynchronizer(sourceClient, destClient *plex.Client, cfg *config.Config, store *state.Store, logger *logger.Logger) *Synchronizer {
+	policies := cfg.Metadata.Policies
+	if policies == nil {
+		policies = config.DefaultSyncPolicies()
+	}
+
 	return &Synchronizer{
-		sourceClient: sourceClient,
-		destClient:   destClient,
-		logger:       logger,
+		sourceClient:     sourceClient,
+		destClient:       destClient,
+		progressMinDelta: cfg.Metadata.ProgressMinDelta,
+		policies:         policies,
+		syncLabel:        cfg.SyncLabel,
+		playlists:        cfg.Metadata.Playlists,
+		lockFields:       cfg.Metadata.LockFields,
+		syncMarkers:      cfg.Metadata.SyncMarkers,
+		store:            store,
+		baseline:         loadBaseline(store, logger),
+		artwork:          loadArtworkCache(store, logger),
+		logger:           logger,
 	}
 }
 
+// SaveState persists the last synced field values and the artwork cache
+func (s *Synchronizer) SaveState() error {
+	s.baseline.mu.Lock()
+	err := s.store.Save(baselineDocument, s.baseline)
+	s.baseline.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.artwork.mu.Lock()
+	defer s.artwork.mu.Unlock()
+	return s.store.Save(artworkCacheDocument, s.artwork)
+}
+
 // SyncMetadata synchronizes metadata for a single media item using concrete plex types
 func (s *Synchronizer) SyncMetadata(sourceItem interface{}, destRatingKey string) error {
 	// Extract rating key and title from concrete plex types
@@ -44,7 +84,7 @@ func (s *Synchronizer) SyncMetadata(sourceItem interface{}, destRatingKey string
 	var syncErrors []string
 
 	// Sync watched state
-	if err := s.syncWatchedState(sourceRatingKey, destRatingKey); err != nil {
+	if err := s.SyncWatchedState(sourceRatingKey, destRatingKey); err != nil {
 		s.logger.WithError(err).Debug("Failed to sync watched state")
 		syncErrors = append(syncErrors, fmt.Sprintf("watched state: %v", err))
 	}
@@ -81,7 +121,7 @@ func (s *Synchronizer) SyncMetadata(sourceItem interface{}, destRatingKey string
 	return nil
 }
 
-// SyncEnhancedMetadata synchronizes comprehensive metadata using enhanced items with library context
+// SyncEnhancedMetadata synchronizes the policy-controlled metadata field groups using enhanced items with library context
 func (s *Synchronizer) SyncEnhancedMetadata(sourceEnhanced, destEnhanced *discovery.EnhancedMediaItem) error {
 	sourceRatingKey := s.getItemRatingKey(sourceEnhanced.Item)
 	destRatingKey := s.getItemRatingKey(destEnhanced.Item)
@@ -99,29 +139,17 @@ func (s *Synchronizer) SyncEnhancedMetadata(sourceEnhanced, destEnhanced *discov
 		"title":             itemTitle,
 	}).Debug("Starting enhanced metadata synchronization with library context")
 
-	var syncErrors []string
-
-	// Sync watched state
-	if err := s.syncWatchedState(sourceRatingKey, destRatingKey); err != nil {
-		s.logger.WithError(err).Debug("Failed to sync watched state")
-		syncErrors = append(syncErrors, fmt.Sprintf("watched state: %v", err))
-	}
-
-	// Sync metadata based on item type with library context
-	switch sourceItem := sourceEnhanced.Item.(type) {
-	case plex.Movie:
-		if err := s.syncEnhancedMovieMetadata(sourceItem, destRatingKey, destEnhanced.LibraryID); err != nil {
-			syncErrors = append(syncErrors, fmt.Sprintf("enhanced movie metadata: %v", err))
-		}
-	case plex.TVShow:
-		if err := s.syncEnhancedTVShowMetadata(sourceItem, destRatingKey, destEnhanced.LibraryID); err != nil {
-			syncErrors = append(syncErrors, fmt.Sprintf("enhanced TV show metadata: %v", err))
-		}
-	default:
+	// Watched state and progress are synced separately (SyncWatchedState), for every match and home user
+	sourceFields, sourceOK := fieldsOf(sourceEnhanced)
+	destFields, destOK := fieldsOf(destEnhanced)
+	if !sourceOK || !destOK {
 		s.logger.WithField("item_type", fmt.Sprintf("%T", sourceEnhanced.Item)).Debug("Unsupported item type for enhanced sync")
-		syncErrors = append(syncErrors, "unsupported item type")
+		return fmt.Errorf("enhanced metadata sync partially failed: %v", []string{"unsupported item type"})
 	}
 
+	// Sync each field group according to its direction and conflict policy
+	syncErrors := s.syncFieldGroups(sourceFields, destFields)
+
 	if len(syncErrors) > 0 {
 		s.logger.WithFields(map[string]interface{}{
 			"source_rating_key": sourceRatingKey,
@@ -139,68 +167,109 @@ func (s *Synchronizer) SyncEnhancedMetadata(sourceEnhanced, destEnhanced *discov
 	return nil
 }
 
-// syncWatchedState synchronizes watched state between source and destination
-func (s *Synchronizer) syncWatchedState(sourceRatingKey, destRatingKey string) error {
+// SyncWatchedState synchronizes watched state, resume position and stream selections between source and destination
+func (s *Synchronizer) SyncWatchedState(sourceRatingKey, destRatingKey string) error {
+	return s.syncWatchedStateBetween("", s.sourceClient, s.destClient, sourceRatingKey, destRatingKey)
+}
+
+// syncWatchedStateBetween synchronizes watched state, resume position and stream selections using the given clients,
+// which act as the server owner or as one Plex Home user (scope) on each side
+func (s *Synchronizer) syncWatchedStateBetween(scope string, sourceClient, destClient *plex.Client, sourceRatingKey, destRatingKey string) error {
 	// Get watched state from source
-	sourceWatchedState, err := s.sourceClient.GetWatchedState(sourceRatingKey)
+	sourceWatchedState, err := sourceClient.GetWatchedState(sourceRatingKey)
 	if err != nil {
 		return fmt.Errorf("failed to get source watched state: %w", err)
 	}
 
 	// Get watched state from destination
-	destWatchedState, err := s.destClient.GetWatchedState(destRatingKey)
+	destWatchedState, err := destClient.GetWatchedState(destRatingKey)
 	if err != nil {
 		return fmt.Errorf("failed to get destination watched state: %w", err)
 	}
 
-	// Determine which state is more recent and sync accordingly
-	syncToDest := false
-	syncToSource := false
+	// Let the watched policy decide; "newest" compares when each side was last viewed
+	sourceNewer := sourceWatchedState.LastViewedAt > destWatchedState.LastViewedAt
+	key := baselineKey(scope, sourceRatingKey, destRatingKey, config.FieldGroupWatched)
+	sourceValue, destValue := fmt.Sprintf("%t", sourceWatchedState.Watched), fmt.Sprintf("%t", destWatchedState.Watched)
+	action := s.reconcile(config.FieldGroupWatched, key, sourceValue, destValue, sourceNewer)
 
-	// If source is watched but destination is not, sync to destination
-	if sourceWatchedState.Watched && !destWatchedState.Watched {
-		if destWatchedState.LastViewedAt == 0 ||
-			sourceWatchedState.LastViewedAt > destWatchedState.LastViewedAt {
-			syncToDest = true
+	// Perform synchronization
+	if action.updateDest {
+		if err := destClient.SetWatchedState(destRatingKey, sourceWatchedState.Watched); err != nil {
+			return fmt.Errorf("failed to sync watched state to destination: %w", err)
+		}
+		s.baseline.set(key, sourceValue)
+		s.logger.LogWatchedStateSync(destRatingKey, "", sourceWatchedState.Watched, destWatchedState.Watched)
+	} else if action.updateSource {
+		if err := sourceClient.SetWatchedState(sourceRatingKey, destWatchedState.Watched); err != nil {
+			return fmt.Errorf("failed to sync watched state to source: %w", err)
 		}
+		s.baseline.set(key, destValue)
+		s.logger.LogWatchedStateSync(sourceRatingKey, "", destWatchedState.Watched, sourceWatchedState.Watched)
 	}
 
-	// If destination is watched but source is not, sync to source
-	if !sourceWatchedState.Watched && destWatchedState.Watched {
-		if sourceWatchedState.LastViewedAt == 0 ||
-			destWatchedState.LastViewedAt > sourceWatchedState.LastViewedAt {
-			syncToSource = true
-		}
+	if err := s.syncViewOffset(scope, sourceClient, destClient, sourceRatingKey, destRatingKey, sourceWatchedState, destWatchedState); err != nil {
+		return err
 	}
+	return s.syncStreamSelection(scope, sourceClient, destClient, sourceRatingKey, destRatingKey, sourceWatchedState, destWatchedState)
+}
 
-	// If both are watched, sync the one with the higher view count or more recent date
-	if sourceWatchedState.Watched && destWatchedState.Watched {
-		if sourceWatchedState.ViewCount > destWatchedState.ViewCount {
-			syncToDest = true
-		} else if destWatchedState.ViewCount > sourceWatchedState.ViewCount {
-			syncToSource = true
-		} else if sourceWatchedState.LastViewedAt > destWatchedState.LastViewedAt {
-			syncToDest = true
-		} else if destWatchedState.LastViewedAt > sourceWatchedState.LastViewedAt {
-			syncToSource = true
-		}
+// syncViewOffset copies the resume position to the other server as the progress policy decides.
+// Offsets that differ by less than the configured minimum delta count as equal, so the progress update
+// (which bumps lastViewedAt on the receiving side) doesn't bounce back on the next cycle.
+func (s *Synchronizer) syncViewOffset(scope string, sourceClient, destClient *plex.Client, sourceRatingKey, destRatingKey string, sourceState, destState *plex.WatchedState) error {
+	if s.policies[config.FieldGroupProgress].Direction == config.DirectionOff {
+		return nil
 	}
 
-	// Perform synchronization
-	if syncToDest {
-		if err := s.destClient.SetWatchedState(destRatingKey, sourceWatchedState.Watched); err != nil {
-			return fmt.Errorf("failed to sync watched state to destination: %w", err)
-		}
-		s.logger.LogWatchedStateSync(destRatingKey, "", sourceWatchedState.Watched, destWatchedState.Watched)
+	key := baselineKey(scope, sourceRatingKey, destRatingKey, config.FieldGroupProgress)
+	differs := func(a, b int) bool {
+		delta := time.Duration(abs(a-b)) * time.Millisecond
+		return delta > 0 && delta >= s.progressMinDelta
 	}
 
-	if syncToSource {
-		if err := s.sourceClient.SetWatchedState(sourceRatingKey, destWatchedState.Watched); err != nil {
-			return fmt.Errorf("failed to sync watched state to source: %w", err)
-		}
-		s.logger.LogWatchedStateSync(sourceRatingKey, "", destWatchedState.Watched, sourceWatchedState.Watched)
+	if !differs(sourceState.ViewOffset, destState.ViewOffset) {
+		s.baseline.set(key, fmt.Sprintf("%d", sourceState.ViewOffset))
+		return nil
+	}
+
+	sourceChanged, destChanged := true, true
+	if base, known := s.baseline.get(key); known {
+		baseOffset, _ := strconv.Atoi(base)
+		sourceChanged = differs(sourceState.ViewOffset, baseOffset)
+		destChanged = differs(destState.ViewOffset, baseOffset)
+	}
+	action := s.decide(config.FieldGroupProgress, sourceChanged, destChanged, sourceState.LastViewedAt > destState.LastViewedAt)
+
+	newer, older := sourceState, destState
+	targetClient, targetRatingKey, direction := destClient, destRatingKey, "source_to_dest"
+	switch {
+	case action.updateDest:
+	case action.updateSource:
+		newer, older = destState, sourceState
+		targetClient, targetRatingKey, direction = sourceClient, sourceRatingKey, "dest_to_source"
+	default:
+		return nil
+	}
+
+	// A zero offset means finished or never started, which the watched flag already covers
+	if newer.ViewOffset == 0 {
+		return nil
 	}
 
+	if err := targetClient.SetViewOffset(targetRatingKey, newer.ViewOffset); err != nil {
+		return fmt.Errorf("failed to sync view offset (%s): %w", direction, err)
+	}
+	s.baseline.set(key, fmt.Sprintf("%d", newer.ViewOffset))
+
+	s.logger.WithFields(map[string]interface{}{
+		"source_rating_key": sourceRatingKey,
+		"dest_rating_key":   destRatingKey,
+		"direction":         direction,
+		"view_offset":       newer.ViewOffset,
+		"previous_offset":   older.ViewOffset,
+	}).Debug("Synced resume position")
+
 	return nil
 }
 
@@ -222,7 +291,7 @@ func (s *Synchronizer) syncMovieMetadata(sourceMovie plex.Movie, destRatingKey s
 	// Sync labels (requires library ID - skip for now)
 	if len(sourceMovie.Label) > 0 {
 		s.logger.Debug("Label sync requires library ID - skipping for now")
-		// labels := s.extractMovieLabels(sourceMovie)
+		// labels := labelTags(sourceMovie.Label)
 		// if err := s.destClient.SetLabels(destRatingKey, libraryID, labels); err != nil {
 		//     errors = append(errors, fmt.Sprintf("labels: %v", err))
 		// }
@@ -251,7 +320,7 @@ func (s *Synchronizer) syncTVShowMetadata(sourceTVShow plex.TVShow, destRatingKe
 	// Sync labels (requires library ID - skip for now)
 	if len(sourceTVShow.Label) > 0 {
 		s.logger.Debug("Label sync requires library ID - skipping for now")
-		// labels := s.extractTVShowLabels(sourceTVShow)
+		// labels := labelTags(sourceTVShow.Label)
 		// if err := s.destClient.SetLabels(destRatingKey, libraryID, labels); err != nil {
 		//     errors = append(errors, fmt.Sprintf("labels: %v", err))
 		// }
@@ -265,162 +334,6 @@ func (s *Synchronizer) syncTVShowMetadata(sourceTVShow plex.TVShow, destRatingKe
 	return nil
 }
 
-// syncEnhancedMovieMetadata synchronizes all movie metadata fields with library context
-func (s *Synchronizer) syncEnhancedMovieMetadata(sourceMovie plex.Movie, destRatingKey, destLibraryID string) error {
-	var errors []string
-
-	// Sync user rating
-	if sourceMovie.UserRating.Value > 0 {
-		if err := s.destClient.SetUserRating(destRatingKey, sourceMovie.UserRating.Value); err != nil {
-			s.logger.WithError(err).Debug("Failed to sync user rating")
-			errors = append(errors, fmt.Sprintf("user rating: %v", err))
-		} else {
-			s.logger.WithFields(map[string]interface{}{
-				"rating_key": destRatingKey,
-				"rating":     sourceMovie.UserRating.Value,
-			}).Debug("Synced user rating")
-		}
-	}
-
-	// Sync labels - now we have the library ID!
-	if len(sourceMovie.Label) > 0 {
-		labels := s.extractMovieLabels(sourceMovie)
-		if err := s.destClient.SetLabels(destRatingKey, destLibraryID, labels); err != nil {
-			s.logger.WithError(err).Debug("Failed to sync labels")
-			errors = append(errors, fmt.Sprintf("labels: %v", err))
-		} else {
-			s.logger.WithFields(map[string]interface{}{
-				"rating_key":  destRatingKey,
-				"library_id":  destLibraryID,
-				"labels":      labels,
-				"label_count": len(labels),
-			}).Debug("Synced labels")
-		}
-	}
-
-	// Sync genres using the existing UpdateMediaField method
-	if len(sourceMovie.Genre) > 0 {
-		genres := s.extractMovieGenres(sourceMovie)
-		if err := s.destClient.UpdateMediaField(destRatingKey, destLibraryID, genres, "genre", "movie"); err != nil {
-			s.logger.WithError(err).Debug("Failed to sync genres")
-			errors = append(errors, fmt.Sprintf("genres: %v", err))
-		} else {
-			s.logger.WithFields(map[string]interface{}{
-				"rating_key":  destRatingKey,
-				"library_id":  destLibraryID,
-				"genres":      genres,
-				"genre_count": len(genres),
-			}).Debug("Synced genres")
-		}
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("enhanced movie metadata sync errors: %v", errors)
-	}
-
-	s.logger.WithFields(map[string]interface{}{
-		"dest_rating_key": destRatingKey,
-		"dest_library_id": destLibraryID,
-	}).Debug("Enhanced movie metadata sync completed")
-	return nil
-}
-
-// syncEnhancedTVShowMetadata synchronizes all TV show metadata fields with library context
-func (s *Synchronizer) syncEnhancedTVShowMetadata(sourceTVShow plex.TVShow, destRatingKey, destLibraryID string) error {
-	var errors []string
-
-	// Sync user rating
-	if sourceTVShow.UserRating.Value > 0 {
-		if err := s.destClient.SetUserRating(destRatingKey, sourceTVShow.UserRating.Value); err != nil {
-			s.logger.WithError(err).Debug("Failed to sync user rating")
-			errors = append(errors, fmt.Sprintf("user rating: %v", err))
-		} else {
-			s.logger.WithFields(map[string]interface{}{
-				"rating_key": destRatingKey,
-				"rating":     sourceTVShow.UserRating.Value,
-			}).Debug("Synced user rating")
-		}
-	}
-
-	// Sync labels - now we have the library ID!
-	if len(sourceTVShow.Label) > 0 {
-		labels := s.extractTVShowLabels(sourceTVShow)
-		if err := s.destClient.SetLabels(destRatingKey, destLibraryID, labels); err != nil {
-			s.logger.WithError(err).Debug("Failed to sync labels")
-			errors = append(errors, fmt.Sprintf("labels: %v", err))
-		} else {
-			s.logger.WithFields(map[string]interface{}{
-				"rating_key":  destRatingKey,
-				"library_id":  destLibraryID,
-				"labels":      labels,
-				"label_count": len(labels),
-			}).Debug("Synced labels")
-		}
-	}
-
-	// Sync genres using the existing UpdateMediaField method
-	if len(sourceTVShow.Genre) > 0 {
-		genres := s.extractTVShowGenres(sourceTVShow)
-		if err := s.destClient.UpdateMediaField(destRatingKey, destLibraryID, genres, "genre", "show"); err != nil {
-			s.logger.WithError(err).Debug("Failed to sync genres")
-			errors = append(errors, fmt.Sprintf("genres: %v", err))
-		} else {
-			s.logger.WithFields(map[string]interface{}{
-				"rating_key":  destRatingKey,
-				"library_id":  destLibraryID,
-				"genres":      genres,
-				"genre_count": len(genres),
-			}).Debug("Synced genres")
-		}
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("enhanced TV show metadata sync errors: %v", errors)
-	}
-
-	s.logger.WithFields(map[string]interface{}{
-		"dest_rating_key": destRatingKey,
-		"dest_library_id": destLibraryID,
-	}).Debug("Enhanced TV show metadata sync completed")
-	return nil
-}
-
-// extractMovieLabels extracts label strings from a Movie
-func (s *Synchronizer) extractMovieLabels(movie plex.Movie) []string {
-	var labels []string
-	for _, label := range movie.Label {
-		labels = append(labels, label.Tag)
-	}
-	return labels
-}
-
-// extractTVShowLabels extracts label strings from a TV Show
-func (s *Synchronizer) extractTVShowLabels(tvshow plex.TVShow) []string {
-	var labels []string
-	for _, label := range tvshow.Label {
-		labels = append(labels, label.Tag)
-	}
-	return labels
-}
-
-// extractMovieGenres extracts genre strings from a Movie
-func (s *Synchronizer) extractMovieGenres(movie plex.Movie) []string {
-	var genres []string
-	for _, genre := range movie.Genre {
-		genres = append(genres, genre.Tag)
-	}
-	return genres
-}
-
-// extractTVShowGenres extracts genre strings from a TV Show
-func (s *Synchronizer) extractTVShowGenres(tvshow plex.TVShow) []string {
-	var genres []string
-	for _, genre := range tvshow.Genre {
-		genres = append(genres, genre.Tag)
-	}
-	return genres
-}
-
 // SyncBulkMetadata synchronizes metadata for multiple items using concrete plex types
 func (s *Synchronizer) SyncBulkMetadata(items []MetadataSync) error {
 	for i, item := range items {
@@ -0,0 +1,96 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nullable-eth/syncarr/internal/plex"
+)
+
+// UserPair links a Plex Home user on the source server to the corresponding user on the destination,
+// with clients that act as those users
+type UserPair struct {
+	SourceUser   string
+	DestUser     string
+	sourceClient *plex.Client
+	destClient   *plex.Client
+}
+
+// ResolveUserPairs enumerates the Plex Home users of both servers, maps them to each other and obtains
+// per-user server tokens. Users are mapped through userMap (lowercased source name -> destination name) first,
+// then by identical name. The owner pair is left out because the regular sync already covers it.
+func (s *Synchronizer) ResolveUserPairs(userMap map[string]string) ([]UserPair, error) {
+	sourceUsers, err := s.sourceClient.GetHomeUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source home users: %w", err)
+	}
+	destUsers, err := s.destClient.GetHomeUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination home users: %w", err)
+	}
+
+	destByName := make(map[string]plex.HomeUser, len(destUsers))
+	for _, user := range destUsers {
+		destByName[strings.ToLower(user.Name())] = user
+	}
+
+	var pairs []UserPair
+	for _, sourceUser := range sourceUsers {
+		sourceName := strings.ToLower(sourceUser.Name())
+		destName, mapped := userMap[sourceName]
+		if !mapped {
+			destName = sourceName
+		}
+
+		destUser, found := destByName[destName]
+		if !found {
+			if mapped {
+				s.logger.WithFields(map[string]interface{}{
+					"source_user": sourceUser.Name(),
+					"dest_user":   destName,
+				}).Warn("Mapped destination home user not found, skipping")
+			} else {
+				s.logger.WithField("source_user", sourceUser.Name()).Debug("No matching destination home user, skipping")
+			}
+			continue
+		}
+		if sourceUser.Admin && destUser.Admin {
+			continue
+		}
+
+		sourceClient, err := s.sourceClient.ForHomeUser(sourceUser)
+		if err != nil {
+			s.logger.WithError(err).WithField("source_user", sourceUser.Name()).Warn("Failed to act as source home user, skipping")
+			continue
+		}
+		destClient, err := s.destClient.ForHomeUser(destUser)
+		if err != nil {
+			s.logger.WithError(err).WithField("dest_user", destUser.Name()).Warn("Failed to act as destination home user, skipping")
+			continue
+		}
+
+		pairs = append(pairs, UserPair{
+			SourceUser:   sourceUser.Name(),
+			DestUser:     destUser.Name(),
+			sourceClient: sourceClient,
+			destClient:   destClient,
+		})
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"source_users": len(sourceUsers),
+		"dest_users":   len(destUsers),
+		"pairs":        len(pairs),
+	}).Info("Resolved Plex Home user pairs")
+
+	return pairs, nil
+}
+
+// SyncUserWatchedState synchronizes watched state, resume position and stream selections of one home user pair
+func (s *Synchronizer) SyncUserWatchedState(pair UserPair, sourceRatingKey, destRatingKey string) error {
+	scope := "user:" + strings.ToLower(pair.SourceUser) + ">" + strings.ToLower(pair.DestUser)
+	if err := s.syncWatchedStateBetween(scope, pair.sourceClient, pair.destClient, sourceRatingKey, destRatingKey); err != nil {
+		return fmt.Errorf("user %s -> %s: %w", pair.SourceUser, pair.DestUser, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package orchestrator
+
+import (
+	"sort"
+
+	"github.com/nullable-eth/syncarr/internal/discovery"
+	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/pkg/types"
+)
+
+// transferSchedule is the outcome of the free-space preflight: the order in which items are
+// transferred and the items deferred to a later cycle because they don't fit
+type transferSchedule struct {
+	order    []int
+	deferred map[int]bool
+}
+
+// planTransferCapacity orders the planned items by the configured priority and checks them against the
+// free space on the destination. Cleanup (Phase 3) has already removed orphaned files at this point, so
+// the measured free space includes the space they released. When cleanup didn't list the destination,
+// the listing is taken here.
+func (s *SyncOrchestrator) planTransferCapacity(items []*discovery.EnhancedMediaItem, plans [][]types.FileTransfer) transferSchedule {
+	schedule := transferSchedule{deferred: make(map[int]bool)}
+
+	if s.destFileSizes == nil && s.config.DestRootDir != "" {
+		destFiles, err := s.fileTransfer.ListFileSizes(s.config.DestRootDir)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to list destination directory contents for capacity check")
+		} else {
+			s.destFileSizes = destFiles
+		}
+	}
+
+	needed := make([]int64, len(plans))
+	var totalNeeded int64
+	for i, plan := range plans {
+		needed[i] = s.bytesNeeded(plan)
+		totalNeeded += needed[i]
+		if plan != nil {
+			schedule.order = append(schedule.order, i)
+		}
+	}
+
+	s.sortByPriority(schedule.order, items, needed)
+
+	// Without a destination listing every file would count as new, which would defer far too much
+	if s.destFileSizes == nil {
+		s.logger.Warn("Destination file listing unavailable, transferring without capacity check")
+		return schedule
+	}
+
+	freeSpace, err := s.fileTransfer.GetFreeSpace(s.config.DestRootDir)
+	if err != nil {
+		s.logger.WithError(err).Warn("Could not determine destination free space, transferring without capacity check")
+		return schedule
+	}
+
+	budget := freeSpace - s.config.Transfer.MinFreeSpace
+	fields := map[string]interface{}{
+		"free_mb":     freeSpace / (1024 * 1024),
+		"reserve_mb":  s.config.Transfer.MinFreeSpace / (1024 * 1024),
+		"needed_mb":   totalNeeded / (1024 * 1024),
+		"policy":      s.config.Transfer.SpacePolicy,
+		"priority":    s.config.Transfer.TransferPriority,
+		"known_files": len(s.destFileSizes),
+	}
+
+	if totalNeeded <= budget {
+		s.logger.WithFields(fields).Info("Destination has enough free space for this cycle")
+		return schedule
+	}
+
+	if s.config.Transfer.SpacePolicy == "abort" {
+		for _, i := range schedule.order {
+			schedule.deferred[i] = true
+		}
+		s.logger.WithFields(fields).Warn("Not enough free space on destination, skipping all transfers this cycle")
+		return schedule
+	}
+
+	// Partial: take items in priority order while they fit; anything that doesn't fit waits for a later cycle
+	for _, i := range schedule.order {
+		if needed[i] <= budget {
+			budget -= needed[i]
+			continue
+		}
+		schedule.deferred[i] = true
+	}
+
+	fields["deferred_items"] = len(schedule.deferred)
+	s.logger.WithFields(fields).Warn("Not enough free space on destination, transferring only the items that fit")
+
+	return schedule
+}
+
+// bytesNeeded returns the destination space a planned item still needs. Files already present with the
+// same size need nothing; replaced files need their full size because they are written to a temp file first.
+func (s *SyncOrchestrator) bytesNeeded(plan []types.FileTransfer) int64 {
+	var needed int64
+	for _, file := range plan {
+		if existing, exists := s.destFileSizes[file.DestPath]; exists && existing == file.Size {
+			continue
+		}
+		needed += file.Size
+	}
+	return needed
+}
+
+// sortByPriority orders item indexes according to TRANSFER_PRIORITY (stable, so ties keep discovery order)
+func (s *SyncOrchestrator) sortByPriority(order []int, items []*discovery.EnhancedMediaItem, needed []int64) {
+	switch s.config.Transfer.TransferPriority {
+	case "smallest":
+		sort.SliceStable(order, func(a, b int) bool { return needed[order[a]] < needed[order[b]] })
+	case "largest":
+		sort.SliceStable(order, func(a, b int) bool { return needed[order[a]] > needed[order[b]] })
+	case "newest":
+		sort.SliceStable(order, func(a, b int) bool { return itemAddedAt(items[order[a]]) > itemAddedAt(items[order[b]]) })
+	case "oldest":
+		sort.SliceStable(order, func(a, b int) bool { return itemAddedAt(items[order[a]]) < itemAddedAt(items[order[b]]) })
+	}
+}
+
+// itemAddedAt returns when an item was added to the source library
+func itemAddedAt(enhancedItem *discovery.EnhancedMediaItem) int {
+	switch v := enhancedItem.Item.(type) {
+	case plex.Movie:
+		return v.AddedAt
+	case plex.TVShow:
+		return v.AddedAt
+	case plex.Season:
+		return v.AddedAt
+	case plex.Episode:
+		return v.AddedAt
+	default:
+		return 0
+	}
+}
@@ -1,9 +1,10 @@
 package orchestrator
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/nullable-eth/syncarr/internal/logger"
 	"github.com/nullable-eth/syncarr/internal/metadata"
 	"github.com/nullable-eth/syncarr/internal/plex"
+	"github.com/nullable-eth/syncarr/internal/state"
 	"github.com/nullable-eth/syncarr/internal/transfer"
+	"github.com/nullable-eth/syncarr/pkg/types"
 )
 
 // SyncOrchestrator coordinates the 7-phase synchronization process
@@ -27,7 +30,8 @@ type SyncOrchestrator struct {
 	contentMatcher   *discovery.ContentMatcher
 	metadataSync     *metadata.Synchronizer
 	lastSyncTime     time.Time
-	syncedFiles      map[string]bool // Track files that should exist on destination
+	syncedFiles      map[string]bool  // Track files that should exist on destination
+	destFileSizes    map[string]int64 // Destination files and sizes of this cycle, left after cleanup (nil if unknown)
 }
 
 // NewSyncOrchestrator creates a new sync orchestrator with all required components
@@ -56,8 +60,23 @@ func NewSyncOrchestrator(cfg *config.Config, log *logger.Logger) (*SyncOrchestra
 	// Initialize content discovery (Phase 1 and 2)
 	orchestrator.contentDiscovery = discovery.NewContentDiscovery(sourceClient, cfg.SyncLabel, log)
 
+	// Persistent state (destination index, checksum cache) shared by the phases below
+	stateStore := state.NewStore(cfg.StateDir, log)
+
 	// Phase 3: Transfer Files - Use configured or auto-detect optimal transfer method
-	if isSSHConfigured(cfg.SSH, log) {
+	if cfg.TransferMethod == "local" {
+		// Destination is mounted into the container, so no SSH connection is needed
+		log.WithFields(map[string]interface{}{
+			"method":        "local",
+			"dest_root_dir": cfg.DestRootDir,
+		}).Info("Using user-configured transfer method")
+
+		fileTransfer, err := transfer.NewTransferrer(transfer.TransferMethodLocal, cfg, stateStore, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file transferrer: %w", err)
+		}
+		orchestrator.fileTransfer = fileTransfer
+	} else if isSSHConfigured(cfg.SSH, log) {
 		var transferMethod transfer.TransferMethod
 
 		// Check if user specified a transfer method via environment variable
@@ -78,7 +97,7 @@ func NewSyncOrchestrator(cfg *config.Config, log *logger.Logger) (*SyncOrchestra
 			transferMethod = transfer.GetOptimalTransferMethod(log)
 		}
 
-		fileTransfer, err := transfer.NewTransferrer(transferMethod, cfg, log)
+		fileTransfer, err := transfer.NewTransferrer(transferMethod, cfg, stateStore, log)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create file transferrer: %w", err)
 		}
@@ -88,17 +107,26 @@ func NewSyncOrchestrator(cfg *config.Config, log *logger.Logger) (*SyncOrchestra
 	}
 
 	// Initialize library manager (Phase 4)
-	orchestrator.libraryManager = discovery.NewLibraryManager(destClient, log)
+	orchestrator.libraryManager = discovery.NewLibraryManager(destClient, cfg.Metadata.ForceRefresh, log)
 
-	// Initialize content matcher (Phase 5)
-	orchestrator.contentMatcher = discovery.NewContentMatcher(sourceClient, destClient, log)
+	// Initialize content matcher (Phase 5) with its persistent destination index
+	orchestrator.contentMatcher = discovery.NewContentMatcher(sourceClient, destClient, stateStore, log)
 
 	// Initialize metadata synchronizer (Phase 6)
-	orchestrator.metadataSync = metadata.NewSynchronizer(sourceClient, destClient, log)
+	orchestrator.metadataSync = metadata.NewSynchronizer(sourceClient, destClient, cfg, stateStore, log)
 
 	return orchestrator, nil
 }
 
+// TransferProgress returns live progress of the current (or last) file transfer phase.
+// The second value is false when file transfers are not configured.
+func (s *SyncOrchestrator) TransferProgress() (transfer.ProgressSnapshot, bool) {
+	if s.fileTransfer == nil {
+		return transfer.ProgressSnapshot{}, false
+	}
+	return s.fileTransfer.Progress().Snapshot(), true
+}
+
 // Close closes all connections and resources
 func (s *SyncOrchestrator) Close() error {
 	var errs []error
@@ -135,21 +163,40 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 	}
 	s.logger.Info("Destination server is available, proceeding with sync")
 
+	// The file endpoint (SSH host or local mount) is checked separately from Plex: when only it is down,
+	// the file phases are skipped and metadata is still synced for content that is already there
+	filesAvailable := s.fileTransfer != nil
+	if filesAvailable {
+		if err := s.fileTransfer.TestConnection(); err != nil {
+			s.logger.WithError(err).WithField("ssh_host", s.config.SSH.Host).Warn("Destination file system is not available, skipping file phases this cycle")
+			filesAvailable = false
+		} else {
+			s.logger.Info("Destination file system is available")
+		}
+	}
+
 	// Phase 1 and 2: Content Discovery and Filtering with Full Metadata
 	s.logger.WithField("sync_label", s.config.SyncLabel).Info("Phase 1 and 2: START - Content Discovery")
 	itemsToSync, err := s.contentDiscovery.DiscoverSyncableContent()
-	if err != nil {
+	discoveryComplete := true
+	if errors.Is(err, discovery.ErrIncompleteDiscovery) {
+		// Content that failed to load would look orphaned, so cleanup waits for a complete discovery
+		s.logger.WithError(err).Warn("Content discovery incomplete, skipping orphaned file cleanup this cycle")
+		discoveryComplete = false
+	} else if err != nil {
 		return fmt.Errorf("content discovery failed: %w", err)
 	}
 
 	// Count items by type for summary
-	var movieCount, showCount, episodeCount int
+	var movieCount, showCount, seasonCount, episodeCount int
 	for _, item := range itemsToSync {
 		switch item.ItemType {
 		case "movie":
 			movieCount++
 		case "show":
 			showCount++
+		case "season":
+			seasonCount++
 		case "episode":
 			episodeCount++
 		}
@@ -159,6 +206,7 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 		"total_items": len(itemsToSync),
 		"movies":      movieCount,
 		"shows":       showCount,
+		"seasons":     seasonCount,
 		"episodes":    episodeCount,
 		"sync_label":  s.config.SyncLabel,
 	}).Info("Phase 1 and 2: FINISH - Content Discovery")
@@ -169,7 +217,10 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 	}
 
 	// Phase 3: Cleanup - Remove files on destination that aren't in current sync list (before transfer to free space and ensure Plex detects removals)
-	if s.fileTransfer != nil {
+	if filesAvailable && !discoveryComplete {
+		s.destFileSizes = nil
+		s.logger.Info("Phase 3: SKIP - Orphaned File Cleanup (content discovery incomplete)")
+	} else if filesAvailable {
 		s.logger.Info("Phase 3: START - Orphaned File Cleanup")
 		if err := s.cleanupOrphanedFiles(itemsToSync); err != nil {
 			s.logger.WithError(err).Warn("Failed to cleanup orphaned files, continuing")
@@ -178,24 +229,78 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 		}
 	}
 
-	// Phase 4: File Transfer (skip if SSH not configured)
-	if s.fileTransfer != nil {
+	// Phase 4: File Transfer (skip if neither SSH nor a local destination is configured, or it is unreachable)
+	if filesAvailable {
 		s.logger.Info("Phase 4: START - File Transfer")
 
 		// Clear the synced files map for this cycle
 		s.syncedFiles = make(map[string]bool)
 
+		// Plan every item's files up front so the cycle's total size is known for progress and ETA
 		totalItems := len(itemsToSync)
-		var transferredCount, errorCount int
-
+		var transferredCount, errorCount, deferredCount int
+		plans := make([][]types.FileTransfer, totalItems)
+		var plannedFiles int
+		var plannedBytes int64
 		for i, enhancedItem := range itemsToSync {
+			batch, err := s.planEnhancedItemTransfer(enhancedItem)
+			if err != nil {
+				s.logger.WithError(err).WithField("item", s.getEnhancedItemTitle(enhancedItem)).Error("Failed to plan file transfer for enhanced item")
+				errorCount++
+				continue
+			}
+			plans[i] = batch
+			plannedFiles += len(batch)
+			for _, file := range batch {
+				plannedBytes += file.Size
+			}
+		}
+
+		s.logger.WithFields(map[string]interface{}{
+			"planned_files":   plannedFiles,
+			"planned_size_mb": plannedBytes / (1024 * 1024),
+		}).Info("Planned file transfers for this cycle")
+
+		// Free-space preflight: order items by priority and defer whatever doesn't fit
+		schedule := s.planTransferCapacity(itemsToSync, plans)
+		deferredCount = len(schedule.deferred)
+		for i := range schedule.deferred {
+			plannedFiles -= len(plans[i])
+			for _, file := range plans[i] {
+				plannedBytes -= file.Size
+			}
+		}
+
+		progress := s.fileTransfer.Progress()
+		progress.StartCycle(plannedFiles, plannedBytes)
+
+		for position, i := range schedule.order {
+			enhancedItem := itemsToSync[i]
+			if schedule.deferred[i] {
+				continue
+			}
+
 			s.logger.WithFields(map[string]interface{}{
-				"progress":   fmt.Sprintf("%d/%d", i+1, totalItems),
+				"progress":   fmt.Sprintf("%d/%d", position+1, totalItems),
 				"title":      s.getEnhancedItemTitle(enhancedItem),
 				"library_id": enhancedItem.LibraryID,
 			}).Debug("Transferring enhanced item files")
 
-			if err := s.transferEnhancedItemFiles(enhancedItem); err != nil {
+			if err := s.transferItemBatch(plans[i]); err != nil {
+				// The transfer layer checks the bandwidth schedule before every file or directory
+				if errors.Is(err, transfer.ErrTransferWindowClosed) {
+					remaining := 0
+					for _, j := range schedule.order[position:] {
+						if !schedule.deferred[j] {
+							remaining++
+						}
+					}
+					deferredCount += remaining
+					s.logger.WithFields(map[string]interface{}{
+						"deferred_items": remaining,
+					}).Info("Transfer window closed, deferring remaining items to the next cycle")
+					break
+				}
 				s.logger.WithError(err).WithField("item", s.getEnhancedItemTitle(enhancedItem)).Error("Failed to transfer enhanced item files")
 				errorCount++
 				continue
@@ -203,20 +308,25 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 			transferredCount++
 
 			// Log progress summary every 100 items or at significant milestones
-			if (i+1)%100 == 0 || (i+1) == totalItems || (i+1)%500 == 0 {
+			if (position+1)%100 == 0 || (position+1) == totalItems || (position+1)%500 == 0 {
+				snapshot := progress.Snapshot()
 				s.logger.WithFields(map[string]interface{}{
-					"completed": i + 1,
-					"total":     totalItems,
-					"progress":  fmt.Sprintf("%.1f%%", float64(i+1)/float64(totalItems)*100),
+					"completed":     position + 1,
+					"total":         totalItems,
+					"progress":      fmt.Sprintf("%.1f%%", float64(position+1)/float64(totalItems)*100),
+					"rate_mbps":     snapshot.BytesPerSecond / (1024 * 1024),
+					"cycle_eta_min": snapshot.CycleETA.Minutes(),
 				}).Debug("File transfer progress")
 			}
 		}
+		progress.FinishCycle()
 
 		// Log final transfer summary
 		s.logger.WithFields(map[string]interface{}{
 			"total_items":  totalItems,
 			"transferred":  transferredCount,
 			"errors":       errorCount,
+			"deferred":     deferredCount,
 			"success_rate": fmt.Sprintf("%.1f%%", float64(transferredCount)/float64(totalItems)*100),
 		}).Info("Phase 4: FINISH - File Transfer")
 
@@ -226,21 +336,27 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 			return fmt.Errorf("library refresh failed: %w", err)
 		}
 		s.logger.Info("Phase 5: FINISH - Library Refresh")
+	} else if s.fileTransfer != nil {
+		s.logger.Info("Phase 3: SKIP - Orphaned File Cleanup (destination file system unavailable)")
+		s.logger.Info("Phase 4: SKIP - File Transfer (destination file system unavailable)")
+		s.logger.Info("Phase 5: SKIP - Library Refresh (no files transferred)")
 	} else {
-		s.logger.Info("Phase 4: SKIP - File Transfer (SSH not configured)")
+		s.logger.Info("Phase 4: SKIP - File Transfer (no SSH or local destination configured)")
 		s.logger.Info("Phase 5: SKIP - Library Refresh (no files transferred)")
 	}
 
 	// Phase 6: Content Matching
 	s.logger.Info("Phase 6: START - Content Matching")
-	matches, err := s.contentMatcher.MatchItemsByFilename(itemsToSync)
+	// Labeled seasons are matched and synced through their episodes
+	itemsToMatch := s.contentMatcher.ExpandSeasons(itemsToSync)
+	matches, err := s.contentMatcher.MatchItemsByFilename(itemsToMatch)
 	if err != nil {
 		return fmt.Errorf("content matching failed: %w", err)
 	}
 	s.logger.WithFields(map[string]interface{}{
-		"source_items": len(itemsToSync),
+		"source_items": len(itemsToMatch),
 		"matches":      len(matches),
-		"success_rate": fmt.Sprintf("%.1f%%", float64(len(matches))/float64(len(itemsToSync))*100),
+		"success_rate": fmt.Sprintf("%.1f%%", float64(len(matches))/float64(len(itemsToMatch))*100),
 	}).Info("Phase 6: FINISH - Content Matching")
 
 	// Phase 7: Metadata Synchronization
@@ -248,7 +364,23 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 	if len(matches) == 0 {
 		s.logger.Info("Phase 7: SKIP - Metadata Synchronization (no matches found)")
 	} else {
-		success, errors, skipped := s.syncAllMetadata(matches)
+		// Plex Home users have their own watch history, reachable only with per-user tokens
+		var userPairs []metadata.UserPair
+		if s.config.Metadata.SyncHomeUsers {
+			pairs, err := s.metadataSync.ResolveUserPairs(s.config.Metadata.UserMap)
+			if err != nil {
+				s.logger.WithError(err).Warn("Failed to resolve Plex Home users, syncing owner watched state only")
+			}
+			userPairs = pairs
+		}
+
+		success, errors, skipped := s.syncAllMetadata(matches, userPairs)
+
+		// Playlists only reference matched items, so they are synced once the items' metadata is in place
+		if err := s.metadataSync.SyncPlaylists(s.contentMatcher); err != nil {
+			s.logger.WithError(err).Warn("Failed to sync playlists")
+		}
+
 		s.logger.WithFields(map[string]interface{}{
 			"total":   len(matches),
 			"success": success,
@@ -261,32 +393,17 @@ func (s *SyncOrchestrator) RunSyncCycle() error {
 	return nil
 }
 
-// transferEnhancedItemFiles handles file transfer for an enhanced item with path mapping
-func (s *SyncOrchestrator) transferEnhancedItemFiles(enhancedItem *discovery.EnhancedMediaItem) error {
-	// Extract file paths based on item type from the enhanced item
-	var filePaths []string
-
-	switch v := enhancedItem.Item.(type) {
-	case plex.Movie:
-		filePaths = s.extractMovieFilePaths(v)
-	case plex.TVShow:
-		// For TV shows, get all episodes and their file paths
-		episodes, err := s.sourceClient.GetAllTVShowEpisodes(v.RatingKey.String())
-		if err != nil {
-			return fmt.Errorf("failed to get episodes for TV show %s: %w", v.Title, err)
-		}
-		for _, episode := range episodes {
-			episodePaths := s.extractEpisodeFilePaths(episode)
-			filePaths = append(filePaths, episodePaths...)
-		}
-	case plex.Episode:
-		filePaths = s.extractEpisodeFilePaths(v)
-	default:
-		s.logger.WithField("item_type", fmt.Sprintf("%T", enhancedItem.Item)).Warn("Unknown enhanced item type for file transfer")
-		return nil
+// planEnhancedItemTransfer resolves the files (with sidecars) of an enhanced item and maps them to destination paths
+func (s *SyncOrchestrator) planEnhancedItemTransfer(enhancedItem *discovery.EnhancedMediaItem) ([]types.FileTransfer, error) {
+	// Resolved the same way as cleanup's expected files, so cleanup never deletes what is transferred
+	filePaths, err := s.extractEnhancedItemFilePaths(enhancedItem)
+	if err != nil {
+		return nil, err
 	}
 
-	// Transfer each file with path mapping
+	// Map every file (including sidecars from findRelatedFiles); the item is later handed over as one batch,
+	// which the transferrer groups by directory (one rsync per group instead of one per file)
+	batch := []types.FileTransfer{}
 	for _, sourcePath := range filePaths {
 		if sourcePath == "" {
 			continue
@@ -299,9 +416,10 @@ func (s *SyncOrchestrator) transferEnhancedItemFiles(enhancedItem *discovery.Enh
 			continue
 		}
 
-		// Check if local file exists
-		if _, err := os.Stat(localPath); os.IsNotExist(err) {
-			s.logger.WithField("local_path", localPath).Warn("Local file does not exist, skipping transfer")
+		// Check if source file exists (locally, or on the source host in pull mode)
+		size, err := s.fileTransfer.StatSource(localPath)
+		if errors.Is(err, transfer.ErrRemoteNotFound) {
+			s.logger.WithField("local_path", localPath).Warn("Source file does not exist, skipping transfer")
 			continue
 		}
 
@@ -315,16 +433,32 @@ func (s *SyncOrchestrator) transferEnhancedItemFiles(enhancedItem *discovery.Enh
 		// Track this file as synced (should exist on destination) before transfer
 		s.syncedFiles[destPath] = true
 
-		// Transfer the file
-		if err := s.fileTransfer.TransferFile(localPath, destPath); err != nil {
-			s.logger.WithError(err).WithFields(map[string]interface{}{
-				"local_path": localPath,
-				"dest_path":  destPath,
+		batch = append(batch, types.FileTransfer{SourcePath: localPath, DestPath: destPath, Size: size})
+	}
+
+	return batch, nil
+}
+
+// transferItemBatch transfers the planned files of one item and logs per-file failures
+func (s *SyncOrchestrator) transferItemBatch(batch []types.FileTransfer) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	results, err := s.fileTransfer.TransferFiles(batch)
+	if err != nil {
+		return fmt.Errorf("failed to transfer files: %w", err)
+	}
+
+	// Transfer completed (detailed logging handled in transfer layer), report per-file failures
+	for _, result := range results {
+		if result.Status == types.FileTransferFailed {
+			s.logger.WithFields(map[string]interface{}{
+				"local_path": result.SourcePath,
+				"dest_path":  result.DestPath,
+				"error":      result.Error,
 			}).Error("Failed to transfer file")
-			continue
 		}
-
-		// Transfer completed successfully (detailed logging handled in transfer layer)
 	}
 
 	return nil
@@ -358,19 +492,14 @@ func (s *SyncOrchestrator) findRelatedFiles(mainFilePath string) []string {
 	}
 
 	// List all files in the directory
-	entries, err := os.ReadDir(localDir)
+	entryNames, err := s.fileTransfer.ListSourceFiles(localDir)
 	if err != nil {
 		s.logger.WithError(err).WithField("local_dir", localDir).Debug("Failed to read directory for related files")
 		return allPaths
 	}
 
 	// Find files with matching prefix
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		entryName := entry.Name()
+	for _, entryName := range entryNames {
 		if strings.HasPrefix(entryName, prefix+".") && entryName != filename {
 			// Construct the full source path for the related file
 			relatedSourcePath := filepath.Join(dir, entryName)
@@ -413,8 +542,10 @@ func (s *SyncOrchestrator) extractEpisodeFilePaths(episode plex.Episode) []strin
 	return paths
 }
 
-// extractEnhancedItemFilePaths extracts file paths from an enhanced media item
-func (s *SyncOrchestrator) extractEnhancedItemFilePaths(enhancedItem *discovery.EnhancedMediaItem) []string {
+// extractEnhancedItemFilePaths extracts file paths from an enhanced media item, for both the transfer plan and
+// cleanup. Failing to list a show's or season's episodes is an error rather than an empty list, since cleanup
+// would delete their files otherwise.
+func (s *SyncOrchestrator) extractEnhancedItemFilePaths(enhancedItem *discovery.EnhancedMediaItem) ([]string, error) {
 	var filePaths []string
 
 	switch v := enhancedItem.Item.(type) {
@@ -424,8 +555,17 @@ func (s *SyncOrchestrator) extractEnhancedItemFilePaths(enhancedItem *discovery.
 		// For TV shows, get all episodes and their file paths
 		episodes, err := s.sourceClient.GetAllTVShowEpisodes(v.RatingKey.String())
 		if err != nil {
-			s.logger.WithError(err).WithField("show", v.Title).Debug("Failed to get episodes for TV show during cleanup")
-			return []string{}
+			return nil, fmt.Errorf("failed to get episodes for TV show %s: %w", v.Title, err)
+		}
+		for _, episode := range episodes {
+			episodePaths := s.extractEpisodeFilePaths(episode)
+			filePaths = append(filePaths, episodePaths...)
+		}
+	case plex.Season:
+		// Partially synced show: only the labeled season's episodes are transferred and expected on the destination
+		episodes, err := s.sourceClient.GetSeasonEpisodes(v.RatingKey.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get episodes for season %s of %s: %w", v.Title, v.ParentTitle, err)
 		}
 		for _, episode := range episodes {
 			episodePaths := s.extractEpisodeFilePaths(episode)
@@ -434,14 +574,17 @@ func (s *SyncOrchestrator) extractEnhancedItemFilePaths(enhancedItem *discovery.
 	case plex.Episode:
 		filePaths = s.extractEpisodeFilePaths(v)
 	default:
-		s.logger.WithField("item_type", fmt.Sprintf("%T", enhancedItem.Item)).Debug("Unknown enhanced item type for cleanup")
+		s.logger.WithField("item_type", fmt.Sprintf("%T", enhancedItem.Item)).Warn("Unknown enhanced item type, no files resolved")
 	}
 
-	return filePaths
+	return filePaths, nil
 }
 
 // cleanupOrphanedFiles removes files on the destination that aren't in the current sync list
 func (s *SyncOrchestrator) cleanupOrphanedFiles(itemsToSync []*discovery.EnhancedMediaItem) error {
+	// The previous cycle's listing is stale; it is replaced once the destination is listed below
+	s.destFileSizes = nil
+
 	if s.config.DestRootDir == "" {
 		s.logger.Debug("No destination root directory configured, skipping cleanup")
 		return nil
@@ -449,13 +592,18 @@ func (s *SyncOrchestrator) cleanupOrphanedFiles(itemsToSync []*discovery.Enhance
 
 	s.logger.WithField("dest_root", s.config.DestRootDir).Info("Scanning destination directory for orphaned files")
 
-	// Build expected files map from current sync items
+	// Build expected files map from current sync items. Labeled seasons and episodes of partially
+	// synced shows only contribute their own files, so the rest of such a show is intentionally absent.
 	expectedFiles := make(map[string]bool)
 	for _, enhancedItem := range itemsToSync {
-		filePaths := s.extractEnhancedItemFilePaths(enhancedItem)
+		filePaths, err := s.extractEnhancedItemFilePaths(enhancedItem)
+		if err != nil {
+			// Without the item's full file list its files would look orphaned, so nothing is removed this cycle
+			return fmt.Errorf("failed to resolve expected files of %s: %w", s.getEnhancedItemTitle(enhancedItem), err)
+		}
 		for _, filePath := range filePaths {
 			// Map to destination path
-			destPath, err := s.config.MapLocalPathToDest(filePath)
+			destPath, err := s.mapSourcePathToDest(filePath)
 			if err != nil {
 				s.logger.WithError(err).WithField("source_path", filePath).Debug("Failed to map source path to destination, skipping")
 				continue
@@ -464,14 +612,26 @@ func (s *SyncOrchestrator) cleanupOrphanedFiles(itemsToSync []*discovery.Enhance
 		}
 	}
 
-	// Get list of all files in destination directory
-	destFiles, err := s.fileTransfer.ListDirectoryContents(s.config.DestRootDir)
+	// Get list of all files (with sizes, reused by the free-space preflight) in destination directory
+	destFiles, err := s.fileTransfer.ListFileSizes(s.config.DestRootDir)
 	if err != nil {
 		return fmt.Errorf("failed to list destination directory contents: %w", err)
 	}
+	s.destFileSizes = destFiles
+	destFileCount := len(destFiles)
 
 	orphanedCount := 0
-	for _, destFile := range destFiles {
+	var freedBytes int64
+	for destFile, size := range destFiles {
+		// Temp files of interrupted transfers are kept only if they can still be resumed into an expected file
+		if transfer.IsTempFile(destFile) {
+			if target, resumable := transfer.ResumableTarget(destFile); resumable && s.config.Transfer.ResumeTransfers && expectedFiles[target] {
+				s.logger.WithField("partial_file", destFile).Debug("Keeping partial transfer for resume")
+				continue
+			}
+			s.logger.WithField("temp_file", destFile).Debug("Removing stale temp file from interrupted transfer")
+		}
+
 		// Check if this file is in our current expected files list
 		if !expectedFiles[destFile] {
 			s.logger.WithField("orphaned_file", destFile).Debug("Removing orphaned file from destination")
@@ -480,21 +640,33 @@ func (s *SyncOrchestrator) cleanupOrphanedFiles(itemsToSync []*discovery.Enhance
 				s.logger.WithError(err).WithField("file", destFile).Warn("Failed to delete orphaned file")
 				continue
 			}
+			delete(s.destFileSizes, destFile)
+			freedBytes += size
 			orphanedCount++
 		}
 	}
 
 	s.logger.WithFields(map[string]interface{}{
 		"expected_files": len(expectedFiles),
-		"dest_files":     len(destFiles),
+		"dest_files":     destFileCount,
 		"orphaned_files": orphanedCount,
+		"freed_mb":       freedBytes / (1024 * 1024),
 	}).Debug("Cleanup phase statistics")
 
 	return nil
 }
 
+// mapSourcePathToDest maps a source Plex path to its destination path via the local path mapping
+func (s *SyncOrchestrator) mapSourcePathToDest(sourcePath string) (string, error) {
+	localPath, err := s.config.MapSourcePathToLocal(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	return s.config.MapLocalPathToDest(localPath)
+}
+
 // syncAllMetadata implements Phase 6: Complete metadata transfer with comparison
-func (s *SyncOrchestrator) syncAllMetadata(matches []discovery.ItemMatch) (int, int, int) {
+func (s *SyncOrchestrator) syncAllMetadata(matches []discovery.ItemMatch, userPairs []metadata.UserPair) (int, int, int) {
 	var successCount, errorCount, skippedCount int
 
 	for i, match := range matches {
@@ -513,6 +685,22 @@ func (s *SyncOrchestrator) syncAllMetadata(matches []discovery.ItemMatch) (int,
 			continue
 		}
 
+		// Watched state and resume position change on every play, so they are synced regardless of metadata differences
+		if match.SourceItem.ItemType == "movie" || match.SourceItem.ItemType == "episode" {
+			sourceRatingKey := s.getEnhancedItemRatingKey(match.SourceItem)
+			if err := s.metadataSync.SyncWatchedState(sourceRatingKey, destRatingKey); err != nil {
+				s.logger.WithError(err).WithField("filename", match.Filename).Warn("Failed to sync watched state")
+			}
+			for _, pair := range userPairs {
+				if err := s.metadataSync.SyncUserWatchedState(pair, sourceRatingKey, destRatingKey); err != nil {
+					s.logger.WithError(err).WithField("filename", match.Filename).Warn("Failed to sync home user watched state")
+				}
+			}
+			if err := s.metadataSync.SyncMarkers(sourceRatingKey, destRatingKey); err != nil {
+				s.logger.WithError(err).WithField("filename", match.Filename).Warn("Failed to sync markers")
+			}
+		}
+
 		// Compare enhanced metadata before syncing - now we have full metadata for both items
 		needsSync, err := s.compareEnhancedMetadata(match.SourceItem, match.DestItem)
 		if err != nil {
@@ -535,11 +723,11 @@ func (s *SyncOrchestrator) syncAllMetadata(matches []discovery.ItemMatch) (int,
 				"dest_key":   destRatingKey,
 			}).Debug("Syncing enhanced metadata differences")
 
-			// if err := s.syncEnhancedItemMetadata(match.SourceItem, match.DestItem); err != nil {
-			// 	s.logger.WithError(err).WithField("filename", match.Filename).Error("Failed to sync enhanced metadata")
-			// 	errorCount++
-			// 	continue
-			// }
+			if err := s.metadataSync.SyncEnhancedMetadata(match.SourceItem, match.DestItem); err != nil {
+				s.logger.WithError(err).WithField("filename", match.Filename).Error("Failed to sync enhanced metadata")
+				errorCount++
+				continue
+			}
 			successCount++
 		}
 
@@ -553,6 +741,24 @@ func (s *SyncOrchestrator) syncAllMetadata(matches []discovery.ItemMatch) (int,
 		}
 	}
 
+	// Collection details and smart collections are library-level, so they are synced once per matched library pair
+	var libraryPairs []metadata.LibraryPair
+	seenPairs := make(map[metadata.LibraryPair]bool)
+	for _, match := range matches {
+		pair := metadata.LibraryPair{SourceLibraryID: match.SourceItem.LibraryID, DestLibraryID: match.DestItem.LibraryID}
+		if !seenPairs[pair] {
+			seenPairs[pair] = true
+			libraryPairs = append(libraryPairs, pair)
+		}
+	}
+	if err := s.metadataSync.SyncCollections(libraryPairs); err != nil {
+		s.logger.WithError(err).Warn("Failed to sync collections")
+	}
+
+	if err := s.metadataSync.SaveState(); err != nil {
+		s.logger.WithError(err).Warn("Failed to persist metadata sync state")
+	}
+
 	// Log final metadata sync summary
 	s.logger.WithFields(map[string]interface{}{
 		"total_matches": len(matches),
@@ -603,24 +809,12 @@ func (s *SyncOrchestrator) compareMovieMetadata(source, dest plex.Movie) []strin
 	if source.Title != dest.Title {
 		differences = append(differences, fmt.Sprintf("title differs: '%s' vs '%s'", source.Title, dest.Title))
 	}
-	if source.OriginalTitle != dest.OriginalTitle {
-		differences = append(differences, fmt.Sprintf("original title differs: '%s' vs '%s'", source.OriginalTitle, dest.OriginalTitle))
-	}
 	if source.Year != dest.Year {
 		differences = append(differences, fmt.Sprintf("year differs: %d vs %d", source.Year, dest.Year))
 	}
-	if source.Studio != dest.Studio {
-		differences = append(differences, fmt.Sprintf("studio differs: '%s' vs '%s'", source.Studio, dest.Studio))
-	}
-	if source.ContentRating != dest.ContentRating {
-		differences = append(differences, fmt.Sprintf("content rating differs: '%s' vs '%s'", source.ContentRating, dest.ContentRating))
-	}
 	if source.Summary != dest.Summary {
 		differences = append(differences, "summary differs")
 	}
-	if source.Tagline != dest.Tagline {
-		differences = append(differences, fmt.Sprintf("tagline differs: '%s' vs '%s'", source.Tagline, dest.Tagline))
-	}
 
 	// Compare ratings (allow small differences due to precision)
 	if abs(int64(source.UserRating.Value*10-dest.UserRating.Value*10)) > 1 {
@@ -646,6 +840,9 @@ func (s *SyncOrchestrator) compareMovieMetadata(source, dest plex.Movie) []strin
 		differences = append(differences, fmt.Sprintf("collections differ: %v vs %v", s.extractCollectionTags(source.Collection), s.extractCollectionTags(dest.Collection)))
 	}
 
+	// Fields synced through their descriptors (sort title, credits, settings, ...)
+	differences = append(differences, compareEditableFields(plex.EditableFieldValues(source, plex.MovieFields), plex.EditableFieldValues(dest, plex.MovieFields))...)
+
 	// Compare watched state
 	if source.ViewCount != dest.ViewCount {
 		differences = append(differences, fmt.Sprintf("view count differs: %d vs %d", source.ViewCount, dest.ViewCount))
@@ -662,27 +859,15 @@ func (s *SyncOrchestrator) compareTVShowMetadata(source, dest plex.TVShow) []str
 	if source.Title != dest.Title {
 		differences = append(differences, fmt.Sprintf("title differs: '%s' vs '%s'", source.Title, dest.Title))
 	}
-	if source.OriginalTitle != dest.OriginalTitle {
-		differences = append(differences, fmt.Sprintf("original title differs: '%s' vs '%s'", source.OriginalTitle, dest.OriginalTitle))
-	}
 	if source.Year != dest.Year {
 		differences = append(differences, fmt.Sprintf("year differs: %d vs %d", source.Year, dest.Year))
 	}
-	if source.Studio != dest.Studio {
-		differences = append(differences, fmt.Sprintf("studio differs: '%s' vs '%s'", source.Studio, dest.Studio))
-	}
 	if source.Network != dest.Network {
 		differences = append(differences, fmt.Sprintf("network differs: '%s' vs '%s'", source.Network, dest.Network))
 	}
-	if source.ContentRating != dest.ContentRating {
-		differences = append(differences, fmt.Sprintf("content rating differs: '%s' vs '%s'", source.ContentRating, dest.ContentRating))
-	}
 	if source.Summary != dest.Summary {
 		differences = append(differences, "summary differs")
 	}
-	if source.Tagline != dest.Tagline {
-		differences = append(differences, fmt.Sprintf("tagline differs: '%s' vs '%s'", source.Tagline, dest.Tagline))
-	}
 
 	// Compare ratings (allow small differences due to precision)
 	if abs(int64(source.UserRating.Value*10-dest.UserRating.Value*10)) > 1 {
@@ -708,6 +893,9 @@ func (s *SyncOrchestrator) compareTVShowMetadata(source, dest plex.TVShow) []str
 		differences = append(differences, fmt.Sprintf("collections differ: %v vs %v", s.extractCollectionTags(source.Collection), s.extractCollectionTags(dest.Collection)))
 	}
 
+	// Fields synced through their descriptors (sort title, credits, settings, ...)
+	differences = append(differences, compareEditableFields(plex.EditableFieldValues(source, plex.ShowFields), plex.EditableFieldValues(dest, plex.ShowFields))...)
+
 	// Compare watched state
 	if source.ViewCount != dest.ViewCount {
 		differences = append(differences, fmt.Sprintf("view count differs: %d vs %d", source.ViewCount, dest.ViewCount))
@@ -716,6 +904,45 @@ func (s *SyncOrchestrator) compareTVShowMetadata(source, dest plex.TVShow) []str
 	return differences
 }
 
+// compareEditableFields compares the values of descriptor-driven fields (plex.MovieFields etc.); tag lists
+// are compared case-insensitively and regardless of order
+func compareEditableFields(source, dest map[string]plex.FieldValue) []string {
+	names := make([]string, 0, len(source))
+	for name := range source {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var differences []string
+	for _, name := range names {
+		sourceValue, destValue := source[name], dest[name]
+		if sourceValue.Kind == plex.FieldTags {
+			if !sameTagSet(sourceValue.Tags, destValue.Tags) {
+				differences = append(differences, fmt.Sprintf("%s differs: %v vs %v", name, sourceValue.Tags, destValue.Tags))
+			}
+		} else if sourceValue.Text != destValue.Text {
+			differences = append(differences, fmt.Sprintf("%s differs: '%s' vs '%s'", name, sourceValue.Text, destValue.Text))
+		}
+	}
+	return differences
+}
+
+// sameTagSet reports whether two tag lists hold the same tags, ignoring case and order
+func sameTagSet(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, tag := range a {
+		set[strings.ToLower(tag)] = true
+	}
+	other := make(map[string]bool, len(b))
+	for _, tag := range b {
+		if !set[strings.ToLower(tag)] {
+			return false
+		}
+		other[strings.ToLower(tag)] = true
+	}
+	return len(set) == len(other)
+}
+
 // compareTagArrays compares arrays of tags (Genre/Label)
 func (s *SyncOrchestrator) compareTagArrays(source, dest interface{}) bool {
 	sourceTags := s.extractTags(source)
@@ -921,6 +1148,10 @@ func (s *SyncOrchestrator) getItemTitle(item interface{}) string {
 		return v.Title
 	case plex.TVShow:
 		return v.Title
+	case plex.Season:
+		return fmt.Sprintf("%s - %s", v.ParentTitle, v.Title)
+	case plex.Episode:
+		return fmt.Sprintf("%s - %s", v.GrandparentTitle, v.Title)
 	default:
 		return "unknown"
 	}
@@ -933,6 +1164,8 @@ func (s *SyncOrchestrator) getItemRatingKey(item interface{}) string {
 		return v.RatingKey.String()
 	case plex.TVShow:
 		return v.RatingKey.String()
+	case plex.Season:
+		return v.RatingKey.String()
 	case plex.Episode:
 		return v.RatingKey.String()
 	default:
@@ -0,0 +1,122 @@
+package plex
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Artwork kinds, named after their selection endpoints (/library/metadata/{id}/poster etc.)
+const (
+	ArtworkPoster = "poster"
+	ArtworkArt    = "art"
+	ArtworkTheme  = "theme"
+)
+
+// GetArtworkPaths retrieves the paths of the selected poster, background and theme of a media item
+func (c *Client) GetArtworkPaths(ratingKey string) (*ArtworkPaths, error) {
+	var response ArtworkPathsResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s", ratingKey), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get media metadata: %w", err)
+	}
+
+	if len(response.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("no item found with rating key %s", ratingKey)
+	}
+	return &response.MediaContainer.Metadata[0], nil
+}
+
+// DownloadArtwork downloads artwork by its metadata path (e.g. the item's thumb, "/library/metadata/123/thumb/1700000000")
+func (c *Client) DownloadArtwork(path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.buildURL(path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", c.config.Token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artwork: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download artwork, status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artwork: %w", err)
+	}
+	return data, nil
+}
+
+// UploadArtwork uploads artwork of the given kind to a media item and selects it
+func (c *Client) UploadArtwork(ratingKey, kind string, data []byte) error {
+	uploadURL := c.buildURL(fmt.Sprintf("/library/metadata/%s/%ss", ratingKey, kind))
+
+	req, err := http.NewRequest("POST", uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", c.config.Token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload %s, status code: %d - Response: %s", kind, resp.StatusCode, string(body))
+	}
+
+	// Plex names uploaded artwork after the SHA-1 of its content
+	sum := sha1.Sum(data)
+	uploadKey := fmt.Sprintf("upload://%ss/%s", kind, hex.EncodeToString(sum[:]))
+	if err := c.selectArtwork(ratingKey, kind, uploadKey); err != nil {
+		return err
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"rating_key": ratingKey,
+		"kind":       kind,
+		"size_bytes": len(data),
+	}).Debug("Uploaded and selected artwork")
+
+	return nil
+}
+
+// selectArtwork makes the artwork with the given key the selected one of its kind
+func (c *Client) selectArtwork(ratingKey, kind, artworkKey string) error {
+	parsedURL, err := url.Parse(c.buildURL(fmt.Sprintf("/library/metadata/%s/%s", ratingKey, kind)))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := parsedURL.Query()
+	params.Set("url", artworkKey)
+	params.Set("X-Plex-Token", c.config.Token)
+	parsedURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("PUT", parsedURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to select %s: %w", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to select %s, status code: %d", kind, resp.StatusCode)
+	}
+	return nil
+}
@@ -226,8 +226,8 @@ func (c *Client) GetAllTVShowEpisodes(ratingKey string) ([]Episode, error) {
 	return episodeResponse.MediaContainer.Metadata, nil
 }
 
-// UpdateMediaField updates a media item's field (labels or genres) with new keywords
-func (c *Client) UpdateMediaField(mediaID, libraryID string, keywords []string, updateField string, mediaType string) error {
+// UpdateMediaField updates a media item's field (labels or genres) with new keywords, locking the field when lockField is set
+func (c *Client) UpdateMediaField(mediaID, libraryID string, keywords []string, updateField string, lockField bool, mediaType string) error {
 	c.logger.WithFields(map[string]interface{}{
 		"media_id":      mediaID,
 		"library_id":    libraryID,
@@ -235,10 +235,10 @@ func (c *Client) UpdateMediaField(mediaID, libraryID string, keywords []string,
 		"keyword_count": len(keywords),
 	}).Debug("Making Plex API call to update media field")
 
-	return c.updateMediaField(mediaID, libraryID, keywords, updateField, c.getMediaTypeForLibraryType(mediaType))
+	return c.updateMediaField(mediaID, libraryID, keywords, updateField, lockField, c.getMediaTypeForLibraryType(mediaType))
 }
 
-// RemoveMediaFieldKeywords removes keywords from a media item's field
+// RemoveMediaFieldKeywords removes keywords from a media item's field, locking the field when lockField is set
 func (c *Client) RemoveMediaFieldKeywords(mediaID, libraryID string, valuesToRemove []string, updateField string, lockField bool, mediaType string) error {
 	return c.removeMediaFieldKeywords(mediaID, libraryID, valuesToRemove, updateField, lockField, c.getMediaTypeForLibraryType(mediaType))
 }
@@ -347,37 +347,28 @@ func (c *Client) IsLibraryScanInProgress() (bool, []Activity, error) {
 	return len(libraryScanActivities) > 0, libraryScanActivities, nil
 }
 
-// GetWatchedState retrieves the watched state for a media item
+// GetWatchedState retrieves the watched state and resume position for a media item
 func (c *Client) GetWatchedState(ratingKey string) (*WatchedState, error) {
-	url := c.buildURL(fmt.Sprintf("/library/metadata/%s", ratingKey))
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("X-Plex-Token", c.config.Token)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	var response WatchedStateResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s", ratingKey), nil, &response); err != nil {
 		return nil, fmt.Errorf("failed to get media metadata: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get media metadata, status code: %d", resp.StatusCode)
+	if len(response.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("no item found with rating key %s", ratingKey)
 	}
 
-	// For now, return default state - TODO: Parse actual response
-	watchedState := &WatchedState{
-		Watched:      false,
-		ViewCount:    0,
-		ViewOffset:   0,
-		LastViewedAt: 0,
-	}
+	watchedState := response.MediaContainer.Metadata[0]
+	watchedState.Watched = watchedState.ViewCount > 0
 
-	c.logger.WithField("rating_key", ratingKey).Debug("Retrieved watched state (parsing not yet implemented)")
-	return watchedState, nil
+	c.logger.WithFields(map[string]interface{}{
+		"rating_key":     ratingKey,
+		"watched":        watchedState.Watched,
+		"view_count":     watchedState.ViewCount,
+		"view_offset":    watchedState.ViewOffset,
+		"last_viewed_at": watchedState.LastViewedAt,
+	}).Debug("Retrieved watched state")
+	return &watchedState, nil
 }
 
 // SetWatchedState sets the watched state for a media item
@@ -426,6 +417,44 @@ func (c *Client) SetWatchedState(ratingKey string, watched bool) error {
 	return nil
 }
 
+// SetViewOffset records a resume position (in milliseconds) for a media item through the timeline progress endpoint
+func (c *Client) SetViewOffset(ratingKey string, offset int) error {
+	parsedURL, err := url.Parse(c.buildURL("/:/progress"))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := parsedURL.Query()
+	params.Set("key", ratingKey)
+	params.Set("identifier", "com.plexapp.plugins.library")
+	params.Set("time", fmt.Sprintf("%d", offset))
+	params.Set("state", "stopped")
+	params.Set("X-Plex-Token", c.config.Token)
+	parsedURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("GET", parsedURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to set view offset: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to set view offset, status code: %d", resp.StatusCode)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"rating_key":  ratingKey,
+		"view_offset": offset,
+	}).Debug("Set view offset")
+
+	return nil
+}
+
 // SetUserRating sets the user rating for a media item (0.0 to 10.0)
 func (c *Client) SetUserRating(ratingKey string, rating float64) error {
 	if rating < 0 || rating > 10 {
@@ -470,21 +499,48 @@ func (c *Client) SetUserRating(ratingKey string, rating float64) error {
 
 // SetLabels sets labels for a media item
 func (c *Client) SetLabels(ratingKey, libraryID string, labels []string) error {
-	return c.UpdateMediaField(ratingKey, libraryID, labels, "label", "movie")
+	return c.UpdateMediaField(ratingKey, libraryID, labels, "label", true, "movie")
 }
 
-// SetTitle sets the title for a media item
-func (c *Client) SetTitle(ratingKey, libraryID, title string) error {
-	return c.updateBasicField(ratingKey, libraryID, "title", title)
-}
+// LockField locks a metadata field (e.g. "thumb" or "art") so agent refreshes leave its value alone
+func (c *Client) LockField(ratingKey, libraryID, fieldName, mediaType string) error {
+	parsedURL, err := url.Parse(c.buildURL(fmt.Sprintf("/library/sections/%s/all", libraryID)))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := parsedURL.Query()
+	params.Set("type", fmt.Sprintf("%d", c.getMediaTypeForLibraryType(mediaType)))
+	params.Set("id", ratingKey)
+	params.Set(fmt.Sprintf("%s.locked", fieldName), "1")
+	params.Set("X-Plex-Token", c.config.Token)
+	parsedURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("PUT", parsedURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", fieldName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to lock %s, status code: %d", fieldName, resp.StatusCode)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"rating_key": ratingKey,
+		"field":      fieldName,
+	}).Debug("Locked field")
 
-// SetSummary sets the summary for a media item
-func (c *Client) SetSummary(ratingKey, libraryID, summary string) error {
-	return c.updateBasicField(ratingKey, libraryID, "summary", summary)
+	return nil
 }
 
-// updateBasicField updates basic text fields like title, summary, etc.
-func (c *Client) updateBasicField(ratingKey, libraryID, fieldName, value string) error {
+// updateBasicField updates basic text fields like title, summary, etc., locking the field when lock is set
+func (c *Client) updateBasicField(ratingKey, libraryID, fieldName, value string, lock bool, mediaType int) error {
 	baseURL := c.buildURL(fmt.Sprintf("/library/sections/%s/all", libraryID))
 
 	parsedURL, err := url.Parse(baseURL)
@@ -493,9 +549,12 @@ func (c *Client) updateBasicField(ratingKey, libraryID, fieldName, value string)
 	}
 
 	params := parsedURL.Query()
-	params.Set("type", "1") // Assume movie for now, could be enhanced
+	params.Set("type", fmt.Sprintf("%d", mediaType))
 	params.Set("id", ratingKey)
-	params.Set(fieldName, value)
+	params.Set(fmt.Sprintf("%s.value", fieldName), value)
+	if lock {
+		params.Set(fmt.Sprintf("%s.locked", fieldName), "1")
+	}
 	params.Set("X-Plex-Token", c.config.Token)
 	parsedURL.RawQuery = params.Encode()
 
@@ -527,7 +586,7 @@ func (c *Client) updateBasicField(ratingKey, libraryID, fieldName, value string)
 // Helper Methods
 
 // updateMediaField is a generic function to update media fields (movies: type=1, TV shows: type=2)
-func (c *Client) updateMediaField(mediaID, libraryID string, keywords []string, updateField string, mediaType int) error {
+func (c *Client) updateMediaField(mediaID, libraryID string, keywords []string, updateField string, lockField bool, mediaType int) error {
 	startTime := time.Now()
 
 	// Build the base URL
@@ -551,7 +610,9 @@ func (c *Client) updateMediaField(mediaID, libraryID string, keywords []string,
 		params.Set(paramName, keyword)
 	}
 
-	params.Set(fmt.Sprintf("%s.locked", updateField), "1")
+	if lockField {
+		params.Set(fmt.Sprintf("%s.locked", updateField), "1")
+	}
 	params.Set("X-Plex-Token", c.config.Token)
 
 	parsedURL.RawQuery = params.Encode()
@@ -602,10 +663,9 @@ func (c *Client) removeMediaFieldKeywords(mediaID, libraryID string, valuesToRem
 	paramName := fmt.Sprintf("%s[].tag.tag-", updateField)
 	params.Set(paramName, combinedValues)
 
+	// An unlocked write leaves the current lock state alone instead of unlocking
 	if lockField {
 		params.Set(fmt.Sprintf("%s.locked", updateField), "1")
-	} else {
-		params.Set(fmt.Sprintf("%s.locked", updateField), "0")
 	}
 	params.Set("X-Plex-Token", c.config.Token)
 
@@ -634,12 +694,18 @@ func (c *Client) removeMediaFieldKeywords(mediaID, libraryID string, valuesToRem
 func (c *Client) getMediaTypeForLibraryType(libraryType string) int {
 	switch libraryType {
 	case "movie":
-		return 1
+		return MediaTypeMovie
 	case "show":
-		return 2
+		return MediaTypeShow
+	case "season":
+		return MediaTypeSeason
+	case "episode":
+		return MediaTypeEpisode
+	case "collection":
+		return MediaTypeCollection
 	default:
 		// Default to 1 for unknown types
-		return 1
+		return MediaTypeMovie
 	}
 }
 
@@ -981,3 +1047,192 @@ func (c *Client) GetTVShowDetails(ratingKey string) (*TVShow, error) {
 
 	return &tvShowResponse.MediaContainer.Metadata[0], nil
 }
+
+// GetTVShowSeasons fetches all seasons (children) of a specific TV show
+func (c *Client) GetTVShowSeasons(showRatingKey string) ([]Season, error) {
+	var seasonResponse SeasonResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s/children", showRatingKey), nil, &seasonResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch TV show seasons: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"show_rating_key": showRatingKey,
+		"season_count":    len(seasonResponse.MediaContainer.Metadata),
+	}).Debug("Retrieved TV show seasons")
+
+	return seasonResponse.MediaContainer.Metadata, nil
+}
+
+// GetSeasonEpisodes fetches all episodes (children) of a specific season
+func (c *Client) GetSeasonEpisodes(seasonRatingKey string) ([]Episode, error) {
+	var episodeResponse EpisodeResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s/children", seasonRatingKey), nil, &episodeResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch season episodes: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"season_rating_key": seasonRatingKey,
+		"episode_count":     len(episodeResponse.MediaContainer.Metadata),
+	}).Debug("Retrieved season episodes")
+
+	return episodeResponse.MediaContainer.Metadata, nil
+}
+
+// GetSeasonDetails fetches detailed metadata for a specific season including labels
+func (c *Client) GetSeasonDetails(ratingKey string) (*Season, error) {
+	var seasonResponse SeasonResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s", ratingKey), nil, &seasonResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch season details: %w", err)
+	}
+
+	if len(seasonResponse.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("no season found with rating key %s", ratingKey)
+	}
+
+	return &seasonResponse.MediaContainer.Metadata[0], nil
+}
+
+// GetEpisodeDetails fetches detailed metadata for a specific episode including labels and media parts
+func (c *Client) GetEpisodeDetails(ratingKey string) (*Episode, error) {
+	var episodeResponse EpisodeResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s", ratingKey), nil, &episodeResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch episode details: %w", err)
+	}
+
+	if len(episodeResponse.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("no episode found with rating key %s", ratingKey)
+	}
+
+	return &episodeResponse.MediaContainer.Metadata[0], nil
+}
+
+// GetSeasonsWithLabel retrieves all seasons in a show library carrying a specific label (server-side filtering)
+func (c *Client) GetSeasonsWithLabel(libraryID, label string) ([]Season, error) {
+	params := url.Values{}
+	params.Set("type", fmt.Sprintf("%d", MediaTypeSeason))
+	params.Set("label", label)
+
+	var seasonResponse SeasonResponse
+	if err := c.getJSON(fmt.Sprintf("/library/sections/%s/all", libraryID), params, &seasonResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch labeled seasons: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"library_id":     libraryID,
+		"label":          label,
+		"labeled_season": len(seasonResponse.MediaContainer.Metadata),
+	}).Debug("Retrieved seasons with label")
+
+	return seasonResponse.MediaContainer.Metadata, nil
+}
+
+// GetEpisodesWithLabel retrieves all episodes in a show library carrying a specific label (server-side filtering)
+func (c *Client) GetEpisodesWithLabel(libraryID, label string) ([]Episode, error) {
+	params := url.Values{}
+	params.Set("type", fmt.Sprintf("%d", MediaTypeEpisode))
+	params.Set("label", label)
+
+	var episodeResponse EpisodeResponse
+	if err := c.getJSON(fmt.Sprintf("/library/sections/%s/all", libraryID), params, &episodeResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch labeled episodes: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"library_id":       libraryID,
+		"label":            label,
+		"labeled_episodes": len(episodeResponse.MediaContainer.Metadata),
+	}).Debug("Retrieved episodes with label")
+
+	return episodeResponse.MediaContainer.Metadata, nil
+}
+
+// getJSON performs an authenticated GET request against the Plex API and decodes the JSON response into target
+func (c *Client) getJSON(path string, params url.Values, target interface{}) error {
+	parsedURL, err := url.Parse(c.buildURL(path))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if len(params) > 0 {
+		parsedURL.RawQuery = params.Encode()
+	}
+
+	return c.sendJSON("GET", parsedURL, target)
+}
+
+// sendJSON performs an authenticated request against the Plex API and decodes the JSON response into target.
+// A nil target discards the response body.
+func (c *Client) sendJSON(method string, requestURL *url.URL, target interface{}) error {
+	req, err := http.NewRequest(method, requestURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", c.config.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("plex API returned status %d", resp.StatusCode)
+	}
+	if target == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// GetSectionItems retrieves lightweight items of one metadata type from a library section.
+// When updatedSince is greater than zero only items updated after that Unix timestamp are returned.
+func (c *Client) GetSectionItems(libraryID string, mediaType int, updatedSince int) ([]IndexItem, error) {
+	params := url.Values{}
+	params.Set("type", fmt.Sprintf("%d", mediaType))
+	params.Set("includeGuids", "1")
+	if updatedSince > 0 {
+		// Plex filter syntax: "updatedAt>>=N" means updatedAt greater than N
+		params.Set("updatedAt>>", fmt.Sprintf("%d", updatedSince))
+	}
+
+	var itemResponse IndexItemResponse
+	if err := c.getJSON(fmt.Sprintf("/library/sections/%s/all", libraryID), params, &itemResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch section items: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"library_id":    libraryID,
+		"media_type":    mediaType,
+		"updated_since": updatedSince,
+		"item_count":    len(itemResponse.MediaContainer.Metadata),
+	}).Debug("Retrieved section items")
+
+	return itemResponse.MediaContainer.Metadata, nil
+}
+
+// GetSectionItemKeys returns the rating keys of all items of one metadata type in a library section
+func (c *Client) GetSectionItemKeys(libraryID string, mediaType int) ([]string, error) {
+	params := url.Values{}
+	params.Set("type", fmt.Sprintf("%d", mediaType))
+
+	var itemResponse IndexItemResponse
+	if err := c.getJSON(fmt.Sprintf("/library/sections/%s/all", libraryID), params, &itemResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch section item keys: %w", err)
+	}
+
+	keys := make([]string, 0, len(itemResponse.MediaContainer.Metadata))
+	for _, item := range itemResponse.MediaContainer.Metadata {
+		keys = append(keys, item.RatingKey.String())
+	}
+	return keys, nil
+}
@@ -0,0 +1,71 @@
+package plex
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// GetCollections lists the collections of a library section
+func (c *Client) GetCollections(libraryID string) ([]PlexCollection, error) {
+	var response CollectionsResponse
+	if err := c.getJSON(fmt.Sprintf("/library/sections/%s/collections", libraryID), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get collections: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"library_id":  libraryID,
+		"collections": len(response.MediaContainer.Metadata),
+	}).Debug("Retrieved collections")
+	return response.MediaContainer.Metadata, nil
+}
+
+// CreateSmartCollection creates a smart collection in a library section from a filter query
+// (the part after "?" of a smart collection's content URI) and returns the new collection
+func (c *Client) CreateSmartCollection(libraryID string, mediaType int, title, filter string) (*PlexCollection, error) {
+	machineID, err := c.GetMachineIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	parsedURL, err := url.Parse(c.buildURL("/library/collections"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := parsedURL.Query()
+	params.Set("type", fmt.Sprintf("%d", mediaType))
+	params.Set("title", title)
+	params.Set("smart", "1")
+	params.Set("sectionId", libraryID)
+	params.Set("uri", fmt.Sprintf("server://%s/com.plexapp.plugins.library/library/sections/%s/all?%s", machineID, libraryID, filter))
+	parsedURL.RawQuery = params.Encode()
+
+	var response CollectionsResponse
+	if err := c.sendJSON("POST", parsedURL, &response); err != nil {
+		return nil, fmt.Errorf("failed to create smart collection %s: %w", title, err)
+	}
+	if len(response.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("plex returned no collection after creating %s", title)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"library_id": libraryID,
+		"title":      title,
+		"filter":     filter,
+	}).Info("Created smart collection")
+
+	return &response.MediaContainer.Metadata[0], nil
+}
+
+// GetTagValues lists the values of a tag field (e.g. "genre", "label" or "director") used by items of the
+// given type in a library section
+func (c *Client) GetTagValues(libraryID, field string, mediaType int) ([]TagValue, error) {
+	params := url.Values{}
+	params.Set("type", fmt.Sprintf("%d", mediaType))
+
+	var response TagValuesResponse
+	if err := c.getJSON(fmt.Sprintf("/library/sections/%s/%s", libraryID, field), params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get %s values: %w", field, err)
+	}
+	return response.MediaContainer.Directory, nil
+}
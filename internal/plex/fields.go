@@ -0,0 +1,173 @@
+package plex
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// FieldKind tells how an editable metadata field is stored and written
+type FieldKind int
+
+const (
+	FieldText FieldKind = iota // Single value, written as {name}.value through the library section edit endpoint
+	FieldTags                  // Tag list, written as {name}[i].tag.tag through the library section edit endpoint
+	FieldPref                  // Per-item setting, written through /library/metadata/{id}/prefs
+)
+
+// EditableField describes an editable metadata field of items of type T and how to read it.
+// Text reads FieldText and FieldPref fields; Tags reads FieldTags fields.
+type EditableField[T any] struct {
+	Name string // Plex field name, as used in edit parameters, field locks and prefs
+	Kind FieldKind
+	Text func(T) string
+	Tags func(T) []string
+}
+
+// FieldValue is the value of one editable field of an item
+type FieldValue struct {
+	Name string
+	Kind FieldKind
+	Text string
+	Tags []string
+}
+
+// MovieFields are the editable movie fields beyond title, summary, labels, genres and collections
+var MovieFields = []EditableField[Movie]{
+	{Name: "titleSort", Kind: FieldText, Text: func(m Movie) string { return m.TitleSort }},
+	{Name: "originalTitle", Kind: FieldText, Text: func(m Movie) string { return m.OriginalTitle }},
+	{Name: "contentRating", Kind: FieldText, Text: func(m Movie) string { return m.ContentRating }},
+	{Name: "studio", Kind: FieldText, Text: func(m Movie) string { return m.Studio }},
+	{Name: "tagline", Kind: FieldText, Text: func(m Movie) string { return m.Tagline }},
+	{Name: "originallyAvailableAt", Kind: FieldText, Text: func(m Movie) string { return m.OriginallyAvailableAt }},
+	{Name: "editionTitle", Kind: FieldText, Text: func(m Movie) string { return m.EditionTitle }},
+	{Name: "director", Kind: FieldTags, Tags: func(m Movie) []string { return directorTags(m.Director) }},
+	{Name: "writer", Kind: FieldTags, Tags: func(m Movie) []string { return writerTags(m.Writer) }},
+	{Name: "producer", Kind: FieldTags, Tags: func(m Movie) []string { return producerTags(m.Producer) }},
+	{Name: "country", Kind: FieldTags, Tags: func(m Movie) []string { return countryTags(m.Country) }},
+	{Name: "useOriginalTitle", Kind: FieldPref, Text: func(m Movie) string { return prefInt(m.UseOriginalTitle) }},
+	{Name: "languageOverride", Kind: FieldPref, Text: func(m Movie) string { return m.LanguageOverride }},
+	{Name: "enableCreditsMarkerGeneration", Kind: FieldPref, Text: func(m Movie) string { return prefInt(m.EnableCreditsMarkerGeneration) }},
+}
+
+// ShowFields are the editable show fields beyond title, summary, labels, genres and collections
+var ShowFields = []EditableField[TVShow]{
+	{Name: "titleSort", Kind: FieldText, Text: func(t TVShow) string { return t.TitleSort }},
+	{Name: "originalTitle", Kind: FieldText, Text: func(t TVShow) string { return t.OriginalTitle }},
+	{Name: "contentRating", Kind: FieldText, Text: func(t TVShow) string { return t.ContentRating }},
+	{Name: "studio", Kind: FieldText, Text: func(t TVShow) string { return t.Studio }},
+	{Name: "tagline", Kind: FieldText, Text: func(t TVShow) string { return t.Tagline }},
+	{Name: "originallyAvailableAt", Kind: FieldText, Text: func(t TVShow) string { return t.OriginallyAvailableAt }},
+	{Name: "director", Kind: FieldTags, Tags: func(t TVShow) []string { return directorTags(t.Director) }},
+	{Name: "writer", Kind: FieldTags, Tags: func(t TVShow) []string { return writerTags(t.Writer) }},
+	{Name: "producer", Kind: FieldTags, Tags: func(t TVShow) []string { return producerTags(t.Producer) }},
+	{Name: "country", Kind: FieldTags, Tags: func(t TVShow) []string { return countryTags(t.Country) }},
+	{Name: "useOriginalTitle", Kind: FieldPref, Text: func(t TVShow) string { return prefInt(t.UseOriginalTitle) }},
+	{Name: "languageOverride", Kind: FieldPref, Text: func(t TVShow) string { return t.LanguageOverride }},
+	{Name: "enableCreditsMarkerGeneration", Kind: FieldPref, Text: func(t TVShow) string { return prefInt(t.EnableCreditsMarkerGeneration) }},
+	{Name: "episodeSort", Kind: FieldPref, Text: func(t TVShow) string { return prefInt(t.EpisodeSort) }},
+	{Name: "flattenSeasons", Kind: FieldPref, Text: func(t TVShow) string { return prefInt(t.FlattenSeasons) }},
+	{Name: "showOrdering", Kind: FieldPref, Text: func(t TVShow) string { return t.ShowOrdering }},
+	{Name: "audioLanguage", Kind: FieldPref, Text: func(t TVShow) string { return t.AudioLanguage }},
+	{Name: "subtitleLanguage", Kind: FieldPref, Text: func(t TVShow) string { return t.SubtitleLanguage }},
+	{Name: "subtitleMode", Kind: FieldPref, Text: func(t TVShow) string { return prefInt(t.SubtitleMode) }},
+}
+
+// EpisodeFields are the editable episode fields beyond title, summary, labels, genres and collections
+var EpisodeFields = []EditableField[Episode]{
+	{Name: "titleSort", Kind: FieldText, Text: func(e Episode) string { return e.TitleSort }},
+	{Name: "contentRating", Kind: FieldText, Text: func(e Episode) string { return e.ContentRating }},
+	{Name: "originallyAvailableAt", Kind: FieldText, Text: func(e Episode) string { return e.OriginallyAvailableAt }},
+	{Name: "director", Kind: FieldTags, Tags: func(e Episode) []string { return directorTags(e.Director) }},
+	{Name: "writer", Kind: FieldTags, Tags: func(e Episode) []string { return writerTags(e.Writer) }},
+}
+
+// EditableFieldValues reads the values of the given editable fields from an item
+func EditableFieldValues[T any](item T, fields []EditableField[T]) map[string]FieldValue {
+	values := make(map[string]FieldValue, len(fields))
+	for _, field := range fields {
+		value := FieldValue{Name: field.Name, Kind: field.Kind}
+		if field.Kind == FieldTags {
+			value.Tags = field.Tags(item)
+		} else {
+			value.Text = field.Text(item)
+		}
+		values[field.Name] = value
+	}
+	return values
+}
+
+// SetTextField sets a single-value field of a media item ("movie", "show", "episode" or "collection"),
+// locking the field when lock is set
+func (c *Client) SetTextField(ratingKey, libraryID, fieldName, value, mediaType string, lock bool) error {
+	return c.updateBasicField(ratingKey, libraryID, fieldName, value, lock, c.getMediaTypeForLibraryType(mediaType))
+}
+
+// SetPref sets a per-item setting such as useOriginalTitle or collectionSort
+func (c *Client) SetPref(ratingKey, name, value string) error {
+	parsedURL, err := url.Parse(c.buildURL(fmt.Sprintf("/library/metadata/%s/prefs", ratingKey)))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set(name, value)
+	parsedURL.RawQuery = params.Encode()
+
+	if err := c.sendJSON("PUT", parsedURL, nil); err != nil {
+		return fmt.Errorf("failed to set %s: %w", name, err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"rating_key": ratingKey,
+		"pref":       name,
+		"value":      value,
+	}).Debug("Set item preference")
+
+	return nil
+}
+
+// prefInt formats an integer item setting; Plex leaves settings at their library default out of the
+// metadata, so a missing setting reads as unset while an explicit 0 (off, never, oldest first) is kept
+func prefInt(value FlexibleInt) string {
+	if !value.Set {
+		return ""
+	}
+	return strconv.Itoa(value.Value)
+}
+
+// directorTags returns the names of directors
+func directorTags(directors []Director) []string {
+	tags := make([]string, 0, len(directors))
+	for _, director := range directors {
+		tags = append(tags, director.Tag)
+	}
+	return tags
+}
+
+// writerTags returns the names of writers
+func writerTags(writers []Writer) []string {
+	tags := make([]string, 0, len(writers))
+	for _, writer := range writers {
+		tags = append(tags, writer.Tag)
+	}
+	return tags
+}
+
+// producerTags returns the names of producers
+func producerTags(producers []Producer) []string {
+	tags := make([]string, 0, len(producers))
+	for _, producer := range producers {
+		tags = append(tags, producer.Tag)
+	}
+	return tags
+}
+
+// countryTags returns the names of countries
+func countryTags(countries []Country) []string {
+	tags := make([]string, 0, len(countries))
+	for _, country := range countries {
+		tags = append(tags, country.Tag)
+	}
+	return tags
+}
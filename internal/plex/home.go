@@ -0,0 +1,124 @@
+package plex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// plexTVURL is the base URL of the plex.tv account API
+	plexTVURL = "https://plex.tv"
+	// plexClientIdentifier identifies SyncArr to plex.tv
+	plexClientIdentifier = "syncarr"
+)
+
+// GetMachineIdentifier returns the unique identifier of the Plex server
+func (c *Client) GetMachineIdentifier() (string, error) {
+	var identity IdentityResponse
+	if err := c.getJSON("/identity", nil, &identity); err != nil {
+		return "", fmt.Errorf("failed to get server identity: %w", err)
+	}
+	if identity.MediaContainer.MachineIdentifier == "" {
+		return "", fmt.Errorf("server identity has no machine identifier")
+	}
+	return identity.MediaContainer.MachineIdentifier, nil
+}
+
+// GetHomeUsers lists the Plex Home users of the account that owns the server (requires the owner's token)
+func (c *Client) GetHomeUsers() ([]HomeUser, error) {
+	var response HomeUsersResponse
+	if err := c.plexTVRequest("GET", "/api/v2/home/users", c.config.Token, &response); err != nil {
+		return nil, fmt.Errorf("failed to list home users: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"host":  c.config.Host,
+		"users": len(response.Users),
+	}).Debug("Retrieved Plex Home users")
+	return response.Users, nil
+}
+
+// ForHomeUser returns a client for the same server that acts as the given Plex Home user.
+// The owner's token is used to switch to the user on plex.tv, and the user's account token is then
+// exchanged for the access token of this server.
+func (c *Client) ForHomeUser(user HomeUser) (*Client, error) {
+	if user.Admin {
+		return c, nil
+	}
+
+	var switched HomeUserSwitchResponse
+	if err := c.plexTVRequest("POST", fmt.Sprintf("/api/v2/home/users/%s/switch", url.PathEscape(user.UUID)), c.config.Token, &switched); err != nil {
+		return nil, fmt.Errorf("failed to switch to home user %s: %w", user.Name(), err)
+	}
+	if switched.AuthToken == "" {
+		return nil, fmt.Errorf("plex.tv returned no token for home user %s", user.Name())
+	}
+
+	machineID, err := c.GetMachineIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	if err := c.plexTVRequest("GET", "/api/v2/resources?includeHttps=1", switched.AuthToken, &resources); err != nil {
+		return nil, fmt.Errorf("failed to list servers of home user %s: %w", user.Name(), err)
+	}
+
+	for _, resource := range resources {
+		if resource.ClientIdentifier != machineID || resource.AccessToken == "" {
+			continue
+		}
+
+		userConfig := *c.config
+		userConfig.Token = resource.AccessToken
+
+		c.logger.WithFields(map[string]interface{}{
+			"host": c.config.Host,
+			"user": user.Name(),
+		}).Debug("Obtained server token for home user")
+
+		return &Client{
+			config:     &userConfig,
+			logger:     c.logger,
+			httpClient: c.httpClient,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("home user %s has no access to server %s", user.Name(), machineID)
+}
+
+// plexTVRequest performs an authenticated request against the plex.tv API and decodes the JSON response into target
+func (c *Client) plexTVRequest(method, path, token string, target interface{}) error {
+	req, err := http.NewRequest(method, plexTVURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("X-Plex-Client-Identifier", plexClientIdentifier)
+	req.Header.Set("X-Plex-Product", "SyncArr")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("plex.tv returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
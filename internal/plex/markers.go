@@ -0,0 +1,53 @@
+package plex
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// GetMarkers retrieves the intro/credits markers and chapter source of a movie or episode
+func (c *Client) GetMarkers(ratingKey string) (*ItemMarkers, error) {
+	params := url.Values{}
+	params.Set("includeMarkers", "1")
+
+	var response ItemMarkersResponse
+	if err := c.getJSON(fmt.Sprintf("/library/metadata/%s", ratingKey), params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get markers: %w", err)
+	}
+
+	if len(response.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("no item found with rating key %s", ratingKey)
+	}
+	return &response.MediaContainer.Metadata[0], nil
+}
+
+// CreateMarker adds an intro or credits marker to a movie or episode
+func (c *Client) CreateMarker(ratingKey string, marker Marker) error {
+	parsedURL, err := url.Parse(c.buildURL("/:/markers"))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("metadataItemID", ratingKey)
+	params.Set("type", marker.Type)
+	params.Set("startTimeOffset", fmt.Sprintf("%d", marker.StartTimeOffset))
+	params.Set("endTimeOffset", fmt.Sprintf("%d", marker.EndTimeOffset))
+	if marker.Final.Value {
+		params.Set("final", "1")
+	}
+	parsedURL.RawQuery = params.Encode()
+
+	if err := c.sendJSON("POST", parsedURL, nil); err != nil {
+		return fmt.Errorf("failed to create %s marker: %w", marker.Type, err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"rating_key": ratingKey,
+		"type":       marker.Type,
+		"start_ms":   marker.StartTimeOffset,
+		"end_ms":     marker.EndTimeOffset,
+	}).Debug("Created marker")
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package plex
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetPlaylists lists the video playlists of the server
+func (c *Client) GetPlaylists() ([]Playlist, error) {
+	params := url.Values{}
+	params.Set("playlistType", "video")
+
+	var response PlaylistsResponse
+	if err := c.getJSON("/playlists", params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get playlists: %w", err)
+	}
+
+	c.logger.WithField("playlists", len(response.MediaContainer.Metadata)).Debug("Retrieved playlists")
+	return response.MediaContainer.Metadata, nil
+}
+
+// GetPlaylistItems lists the entries of a playlist in playlist order, with GUIDs and file information
+func (c *Client) GetPlaylistItems(ratingKey string) ([]PlaylistItem, error) {
+	params := url.Values{}
+	params.Set("includeGuids", "1")
+
+	var response PlaylistItemsResponse
+	if err := c.getJSON(fmt.Sprintf("/playlists/%s/items", ratingKey), params, &response); err != nil {
+		return nil, fmt.Errorf("failed to get playlist items: %w", err)
+	}
+	return response.MediaContainer.Metadata, nil
+}
+
+// CreatePlaylist creates a video playlist holding the given library items in order and returns it
+func (c *Client) CreatePlaylist(title string, ratingKeys []string) (*Playlist, error) {
+	itemsURI, err := c.itemsURI(ratingKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("type", "video")
+	params.Set("title", title)
+	params.Set("smart", "0")
+	params.Set("uri", itemsURI)
+
+	var response PlaylistsResponse
+	if err := c.sendPlaylistRequest("POST", "/playlists", params, &response); err != nil {
+		return nil, fmt.Errorf("failed to create playlist %s: %w", title, err)
+	}
+	if len(response.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("plex returned no playlist after creating %s", title)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"title": title,
+		"items": len(ratingKeys),
+	}).Info("Created playlist")
+
+	return &response.MediaContainer.Metadata[0], nil
+}
+
+// UpdatePlaylist sets the title and summary of a playlist
+func (c *Client) UpdatePlaylist(ratingKey, title, summary string) error {
+	params := url.Values{}
+	params.Set("title", title)
+	params.Set("summary", summary)
+
+	if err := c.sendPlaylistRequest("PUT", fmt.Sprintf("/playlists/%s", ratingKey), params, nil); err != nil {
+		return fmt.Errorf("failed to update playlist %s: %w", ratingKey, err)
+	}
+	return nil
+}
+
+// AddPlaylistItems appends library items to a playlist
+func (c *Client) AddPlaylistItems(ratingKey string, itemRatingKeys []string) error {
+	itemsURI, err := c.itemsURI(itemRatingKeys)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("uri", itemsURI)
+
+	if err := c.sendPlaylistRequest("PUT", fmt.Sprintf("/playlists/%s/items", ratingKey), params, nil); err != nil {
+		return fmt.Errorf("failed to add items to playlist %s: %w", ratingKey, err)
+	}
+	return nil
+}
+
+// RemovePlaylistItem removes one entry from a playlist
+func (c *Client) RemovePlaylistItem(ratingKey string, playlistItemID int) error {
+	if err := c.sendPlaylistRequest("DELETE", fmt.Sprintf("/playlists/%s/items/%d", ratingKey, playlistItemID), nil, nil); err != nil {
+		return fmt.Errorf("failed to remove item from playlist %s: %w", ratingKey, err)
+	}
+	return nil
+}
+
+// MovePlaylistItem moves a playlist entry directly after another entry, or to the top when afterID is 0
+func (c *Client) MovePlaylistItem(ratingKey string, playlistItemID, afterID int) error {
+	params := url.Values{}
+	if afterID != 0 {
+		params.Set("after", fmt.Sprintf("%d", afterID))
+	}
+
+	if err := c.sendPlaylistRequest("PUT", fmt.Sprintf("/playlists/%s/items/%d/move", ratingKey, playlistItemID), params, nil); err != nil {
+		return fmt.Errorf("failed to move item in playlist %s: %w", ratingKey, err)
+	}
+	return nil
+}
+
+// itemsURI builds the server URI that addresses a list of library items, as expected by the playlist endpoints
+func (c *Client) itemsURI(ratingKeys []string) (string, error) {
+	machineID, err := c.GetMachineIdentifier()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("server://%s/com.plexapp.plugins.library/library/metadata/%s", machineID, strings.Join(ratingKeys, ",")), nil
+}
+
+// sendPlaylistRequest sends a playlist request, decoding the response into target unless it is nil
+func (c *Client) sendPlaylistRequest(method, path string, params url.Values, target interface{}) error {
+	parsedURL, err := url.Parse(c.buildURL(path))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	parsedURL.RawQuery = params.Encode()
+
+	return c.sendJSON(method, parsedURL, target)
+}
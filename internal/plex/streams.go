@@ -0,0 +1,40 @@
+package plex
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// SetStreamSelection selects the audio and subtitle streams of a media part for the requesting user.
+// A stream ID of -1 leaves that selection alone; a subtitle stream ID of 0 turns subtitles off.
+func (c *Client) SetStreamSelection(partID, audioStreamID, subtitleStreamID int) error {
+	parsedURL, err := url.Parse(c.buildURL(fmt.Sprintf("/library/parts/%d", partID)))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	params := url.Values{}
+	if audioStreamID >= 0 {
+		params.Set("audioStreamID", fmt.Sprintf("%d", audioStreamID))
+	}
+	if subtitleStreamID >= 0 {
+		params.Set("subtitleStreamID", fmt.Sprintf("%d", subtitleStreamID))
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	params.Set("allParts", "1")
+	parsedURL.RawQuery = params.Encode()
+
+	if err := c.sendJSON("PUT", parsedURL, nil); err != nil {
+		return fmt.Errorf("failed to set stream selection: %w", err)
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"part_id":            partID,
+		"audio_stream_id":    audioStreamID,
+		"subtitle_stream_id": subtitleStreamID,
+	}).Debug("Set stream selection")
+
+	return nil
+}
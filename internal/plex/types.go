@@ -7,6 +7,15 @@ import (
 	"strconv"
 )
 
+// Plex API metadata type identifiers used by the "type" query parameter
+const (
+	MediaTypeMovie      = 1
+	MediaTypeShow       = 2
+	MediaTypeSeason     = 3
+	MediaTypeEpisode    = 4
+	MediaTypeCollection = 18
+)
+
 // Library represents a Plex library
 type Library struct {
 	Key   string `json:"key"`
@@ -55,9 +64,9 @@ type Movie struct {
 	ChapterSource                 string            `json:"chapterSource,omitempty"`
 	PrimaryExtraKey               string            `json:"primaryExtraKey,omitempty"`
 	EditionTitle                  string            `json:"editionTitle,omitempty"`
-	EnableCreditsMarkerGeneration int               `json:"enableCreditsMarkerGeneration,omitempty"`
+	EnableCreditsMarkerGeneration FlexibleInt       `json:"enableCreditsMarkerGeneration,omitempty"`
 	LanguageOverride              string            `json:"languageOverride,omitempty"`
-	UseOriginalTitle              int               `json:"useOriginalTitle,omitempty"`
+	UseOriginalTitle              FlexibleInt       `json:"useOriginalTitle,omitempty"`
 	Slug                          string            `json:"slug,omitempty"`
 	SourceURI                     string            `json:"sourceURI,omitempty"`
 	Label                         []Label           `json:"Label,omitempty"`
@@ -68,6 +77,7 @@ type Movie struct {
 	Role                          []Role            `json:"Role,omitempty"`
 	Country                       []Country         `json:"Country,omitempty"`
 	Collection                    []Collection      `json:"Collection,omitempty"`
+	Field                         []Field           `json:"Field,omitempty"`
 	Guid                          FlexibleGuid      `json:"Guid,omitempty"`
 	Media                         []Media           `json:"Media,omitempty"`
 }
@@ -113,12 +123,12 @@ type TVShow struct {
 	SeasonCount                            int               `json:"seasonCount,omitempty"`
 	LeafCount                              int               `json:"leafCount,omitempty"`
 	ViewedLeafCount                        int               `json:"viewedLeafCount,omitempty"`
-	EnableCreditsMarkerGeneration          int               `json:"enableCreditsMarkerGeneration,omitempty"`
-	EpisodeSort                            int               `json:"episodeSort,omitempty"`
-	FlattenSeasons                         int               `json:"flattenSeasons,omitempty"`
+	EnableCreditsMarkerGeneration          FlexibleInt       `json:"enableCreditsMarkerGeneration,omitempty"`
+	EpisodeSort                            FlexibleInt       `json:"episodeSort,omitempty"`
+	FlattenSeasons                         FlexibleInt       `json:"flattenSeasons,omitempty"`
 	ShowOrdering                           string            `json:"showOrdering,omitempty"`
 	LanguageOverride                       string            `json:"languageOverride,omitempty"`
-	UseOriginalTitle                       int               `json:"useOriginalTitle,omitempty"`
+	UseOriginalTitle                       FlexibleInt       `json:"useOriginalTitle,omitempty"`
 	AudioLanguage                          string            `json:"audioLanguage,omitempty"`
 	SubtitleLanguage                       string            `json:"subtitleLanguage,omitempty"`
 	SubtitleMode                           FlexibleInt       `json:"subtitleMode,omitempty"`
@@ -134,6 +144,7 @@ type TVShow struct {
 	Role                                   []Role            `json:"Role,omitempty"`
 	Country                                []Country         `json:"Country,omitempty"`
 	Collection                             []Collection      `json:"Collection,omitempty"`
+	Field                                  []Field           `json:"Field,omitempty"`
 	Guid                                   FlexibleGuid      `json:"Guid,omitempty"`
 	Media                                  []Media           `json:"Media,omitempty"`
 	Location                               []Location        `json:"Location,omitempty"`
@@ -170,8 +181,29 @@ type Media struct {
 
 // Part represents a media part with file information
 type Part struct {
-	File string `json:"file,omitempty"`
-	Size int64  `json:"size,omitempty"`
+	ID     int      `json:"id,omitempty"`
+	File   string   `json:"file,omitempty"`
+	Size   int64    `json:"size,omitempty"`
+	Stream []Stream `json:"Stream,omitempty"` // Only present in single item metadata
+}
+
+// Stream types of a media part stream
+const (
+	StreamTypeVideo    = 1
+	StreamTypeAudio    = 2
+	StreamTypeSubtitle = 3
+)
+
+// Stream represents a video, audio or subtitle stream of a media part
+type Stream struct {
+	ID           int          `json:"id"`
+	StreamType   int          `json:"streamType"`
+	Index        int          `json:"index,omitempty"` // Position in the container; external subtitles have none
+	Codec        string       `json:"codec,omitempty"`
+	LanguageCode string       `json:"languageCode,omitempty"`
+	Title        string       `json:"title,omitempty"`
+	DisplayTitle string       `json:"displayTitle,omitempty"`
+	Selected     FlexibleBool `json:"selected,omitempty"` // Selected for playback by the requesting user
 }
 
 // FlexibleGuid handles both string and array formats from Plex API
@@ -273,14 +305,20 @@ func (fr FlexibleRating) MarshalJSON() ([]byte, error) {
 // FlexibleInt can handle both string and integer values
 type FlexibleInt struct {
 	Value int
+	Set   bool // Whether the value was present, so an explicit 0 can be told apart from a missing value
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for FlexibleInt
 func (fi *FlexibleInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
 	// Try to unmarshal as an integer first
 	var intValue int
 	if err := json.Unmarshal(data, &intValue); err == nil {
 		fi.Value = intValue
+		fi.Set = true
 		return nil
 	}
 
@@ -289,6 +327,7 @@ func (fi *FlexibleInt) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &stringValue); err == nil {
 		if parsedInt, parseErr := strconv.Atoi(stringValue); parseErr == nil {
 			fi.Value = parsedInt
+			fi.Set = true
 			return nil
 		}
 	}
@@ -368,10 +407,48 @@ type Episode struct {
 	Producer              []Producer        `json:"Producer,omitempty"`
 	Role                  []Role            `json:"Role,omitempty"`
 	Collection            []Collection      `json:"Collection,omitempty"`
+	Field                 []Field           `json:"Field,omitempty"`
 	Guid                  FlexibleGuid      `json:"Guid,omitempty"`
 	Media                 []Media           `json:"Media,omitempty"`
 }
 
+// Season represents a Plex season of a TV show
+type Season struct {
+	RatingKey        FlexibleRatingKey `json:"ratingKey"`
+	Title            string            `json:"title"`
+	Summary          string            `json:"summary,omitempty"`
+	Index            int               `json:"index"` // Season number
+	LeafCount        int               `json:"leafCount,omitempty"`
+	ViewedLeafCount  int               `json:"viewedLeafCount,omitempty"`
+	ViewCount        int               `json:"viewCount,omitempty"`
+	LastViewedAt     int               `json:"lastViewedAt,omitempty"`
+	UserRating       FlexibleRating    `json:"userRating,omitempty"`
+	AddedAt          int               `json:"addedAt,omitempty"`
+	UpdatedAt        int               `json:"updatedAt,omitempty"`
+	Thumb            string            `json:"thumb,omitempty"`
+	Art              string            `json:"art,omitempty"`
+	ParentTitle      string            `json:"parentTitle,omitempty"` // Show title
+	ParentKey        string            `json:"parentKey,omitempty"`
+	ParentRatingKey  FlexibleRatingKey `json:"parentRatingKey,omitempty"`
+	ParentGuid       string            `json:"parentGuid,omitempty"`
+	ParentThumb      string            `json:"parentThumb,omitempty"`
+	ParentTheme      string            `json:"parentTheme,omitempty"`
+	LibrarySectionID FlexibleInt       `json:"librarySectionID,omitempty"`
+	Label            []Label           `json:"Label,omitempty"`
+	Guid             FlexibleGuid      `json:"Guid,omitempty"`
+}
+
+// SeasonContainer holds metadata for seasons
+type SeasonContainer struct {
+	Size     int      `json:"size"`
+	Metadata []Season `json:"Metadata"`
+}
+
+// SeasonResponse represents a Plex API response for seasons
+type SeasonResponse struct {
+	MediaContainer SeasonContainer `json:"MediaContainer"`
+}
+
 // EpisodeContainer holds metadata for episodes
 type EpisodeContainer struct {
 	Size     int       `json:"size"`
@@ -383,12 +460,45 @@ type EpisodeResponse struct {
 	MediaContainer EpisodeContainer `json:"MediaContainer"`
 }
 
+// IndexItem is a lightweight view of a library item used to build file path and GUID indexes
+type IndexItem struct {
+	RatingKey        FlexibleRatingKey `json:"ratingKey"`
+	Type             string            `json:"type"`
+	Title            string            `json:"title"`
+	GrandparentTitle string            `json:"grandparentTitle,omitempty"`
+	UpdatedAt        int               `json:"updatedAt,omitempty"`
+	PlexGuid         string            `json:"guid,omitempty"`
+	Guid             FlexibleGuid      `json:"Guid,omitempty"`
+	Media            []Media           `json:"Media,omitempty"`
+}
+
+// IndexItemContainer holds lightweight library items and the total section size
+type IndexItemContainer struct {
+	Size      int         `json:"size"`
+	TotalSize int         `json:"totalSize"`
+	Metadata  []IndexItem `json:"Metadata"`
+}
+
+// IndexItemResponse represents a Plex API response for lightweight library items
+type IndexItemResponse struct {
+	MediaContainer IndexItemContainer `json:"MediaContainer"`
+}
+
 // WatchedState represents the watched state of a media item
 type WatchedState struct {
 	Watched      bool `json:"watched"`
 	ViewCount    int  `json:"viewCount"`
-	ViewOffset   int  `json:"viewOffset"`
-	LastViewedAt int  `json:"lastViewedAt"`
+	ViewOffset   int  `json:"viewOffset"`   // Resume position in milliseconds (0 = not started or finished)
+	LastViewedAt int  `json:"lastViewedAt"` // Unix timestamp of the last play or progress update
+	// Media carries the parts and their streams, whose selected flags are per user like the watched state
+	Media []Media `json:"Media,omitempty"`
+}
+
+// WatchedStateResponse represents the metadata response used to read an item's watched state
+type WatchedStateResponse struct {
+	MediaContainer struct {
+		Metadata []WatchedState `json:"Metadata"`
+	} `json:"MediaContainer"`
 }
 
 // Activity represents a Plex server activity (like library scanning)
@@ -447,8 +557,192 @@ type Collection struct {
 	Tag string `json:"tag"`
 }
 
+// Field represents the lock state of a metadata field; Plex only lists fields that are locked
+type Field struct {
+	Name   string       `json:"name"`
+	Locked FlexibleBool `json:"locked"`
+}
+
 // Location represents a Plex location
 type Location struct {
 	ID   int    `json:"id"`
 	Path string `json:"path"`
 }
+
+// IdentityResponse represents the response from the /identity endpoint
+type IdentityResponse struct {
+	MediaContainer struct {
+		MachineIdentifier string `json:"machineIdentifier"`
+	} `json:"MediaContainer"`
+}
+
+// HomeUser represents a member of the Plex Home that owns a server
+type HomeUser struct {
+	ID         int    `json:"id"`
+	UUID       string `json:"uuid"`
+	Title      string `json:"title"`    // Display name (the only name managed users have)
+	Username   string `json:"username"` // plex.tv account name, empty for managed users
+	Admin      bool   `json:"admin"`
+	Restricted bool   `json:"restricted"`
+	Protected  bool   `json:"protected"` // Switching to the user requires a PIN
+}
+
+// Name returns the name used to match the user across servers: the username, or the title for managed users
+func (u HomeUser) Name() string {
+	if u.Username != "" {
+		return u.Username
+	}
+	return u.Title
+}
+
+// HomeUsersResponse represents the plex.tv response listing the Plex Home users
+type HomeUsersResponse struct {
+	Users []HomeUser `json:"users"`
+}
+
+// HomeUserSwitchResponse represents the plex.tv response to switching to a Plex Home user
+type HomeUserSwitchResponse struct {
+	AuthToken string `json:"authToken"`
+}
+
+// Resource represents a server or client registered to a plex.tv account
+type Resource struct {
+	Name             string `json:"name"`
+	ClientIdentifier string `json:"clientIdentifier"`
+	Provides         string `json:"provides"`
+	AccessToken      string `json:"accessToken"`
+}
+
+// ArtworkPaths holds the paths of the currently selected artwork of a media item
+type ArtworkPaths struct {
+	Thumb string `json:"thumb"`
+	Art   string `json:"art"`
+	Theme string `json:"theme"`
+}
+
+// ArtworkPathsResponse represents the metadata response used to read an item's selected artwork
+type ArtworkPathsResponse struct {
+	MediaContainer struct {
+		Metadata []ArtworkPaths `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// FlexibleBool can handle boolean values sent as true/false, 0/1 or "0"/"1"
+type FlexibleBool struct {
+	Value bool
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for FlexibleBool
+func (fb *FlexibleBool) UnmarshalJSON(data []byte) error {
+	var boolValue bool
+	if err := json.Unmarshal(data, &boolValue); err == nil {
+		fb.Value = boolValue
+		return nil
+	}
+
+	var flexible FlexibleInt
+	if err := json.Unmarshal(data, &flexible); err == nil {
+		fb.Value = flexible.Value != 0
+	}
+	return nil
+}
+
+// MarshalJSON implements custom JSON marshaling for FlexibleBool
+func (fb FlexibleBool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fb.Value)
+}
+
+// PlexCollection represents a collection in a Plex library section
+type PlexCollection struct {
+	RatingKey      FlexibleRatingKey `json:"ratingKey"`
+	Title          string            `json:"title"`
+	TitleSort      string            `json:"titleSort,omitempty"`
+	Summary        string            `json:"summary,omitempty"`
+	Thumb          string            `json:"thumb,omitempty"`
+	Subtype        string            `json:"subtype,omitempty"` // Type of the items, e.g. "movie" or "show"
+	Smart          FlexibleBool      `json:"smart,omitempty"`
+	Content        string            `json:"content,omitempty"`        // Smart collections: the filter URI, e.g. "/library/sections/1/all?type=1&label=123"
+	CollectionSort FlexibleInt       `json:"collectionSort,omitempty"` // 0 = release date, 1 = alphabetical, 2 = custom
+	UpdatedAt      int               `json:"updatedAt,omitempty"`
+	Field          []Field           `json:"Field,omitempty"`
+}
+
+// CollectionsResponse represents a Plex API response listing collections
+type CollectionsResponse struct {
+	MediaContainer struct {
+		Metadata []PlexCollection `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// TagValue is one value of a tag field (e.g. a genre or label) in a library section.
+// Smart collection filters reference tag values by their key, which is a per-server tag ID.
+type TagValue struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+}
+
+// TagValuesResponse represents a Plex API response listing the values of a tag field
+type TagValuesResponse struct {
+	MediaContainer struct {
+		Directory []TagValue `json:"Directory"`
+	} `json:"MediaContainer"`
+}
+
+// Playlist represents a Plex playlist
+type Playlist struct {
+	RatingKey    FlexibleRatingKey `json:"ratingKey"`
+	Title        string            `json:"title"`
+	Summary      string            `json:"summary,omitempty"`
+	PlaylistType string            `json:"playlistType,omitempty"` // "video", "audio" or "photo"
+	Smart        FlexibleBool      `json:"smart,omitempty"`
+	LeafCount    int               `json:"leafCount,omitempty"`
+	UpdatedAt    int               `json:"updatedAt,omitempty"`
+	Label        []Label           `json:"Label,omitempty"`
+}
+
+// PlaylistsResponse represents a Plex API response listing playlists
+type PlaylistsResponse struct {
+	MediaContainer struct {
+		Metadata []Playlist `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// PlaylistItem represents an entry of a playlist. PlaylistItemID identifies the entry within the playlist
+// and is what removals and moves operate on; RatingKey is the library item it points to.
+type PlaylistItem struct {
+	RatingKey      FlexibleRatingKey `json:"ratingKey"`
+	PlaylistItemID FlexibleInt       `json:"playlistItemID"`
+	Type           string            `json:"type"` // "movie", "episode", ...
+	Title          string            `json:"title"`
+	Guid           FlexibleGuid      `json:"Guid,omitempty"`
+	Media          []Media           `json:"Media,omitempty"`
+}
+
+// PlaylistItemsResponse represents a Plex API response listing the entries of a playlist
+type PlaylistItemsResponse struct {
+	MediaContainer struct {
+		Metadata []PlaylistItem `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// Marker represents an intro or credits marker of a movie or episode; offsets are in milliseconds
+type Marker struct {
+	ID              int          `json:"id,omitempty"`
+	Type            string       `json:"type"` // "intro" or "credits"
+	StartTimeOffset int          `json:"startTimeOffset"`
+	EndTimeOffset   int          `json:"endTimeOffset"`
+	Final           FlexibleBool `json:"final,omitempty"` // Credits marker that runs to the end of the item
+}
+
+// ItemMarkers holds the markers and chapter source of a movie or episode
+type ItemMarkers struct {
+	ChapterSource string   `json:"chapterSource,omitempty"`
+	Marker        []Marker `json:"Marker,omitempty"`
+}
+
+// ItemMarkersResponse represents the metadata response used to read an item's markers
+type ItemMarkersResponse struct {
+	MediaContainer struct {
+		Metadata []ItemMarkers `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
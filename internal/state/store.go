@@ -0,0 +1,102 @@
+// Package state provides persistence for small JSON documents (indexes and caches) between sync cycles.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+)
+
+// Store persists JSON documents in a state directory. With an empty directory the store is
+// disabled and callers simply keep their state in memory for the lifetime of the process.
+type Store struct {
+	dir    string
+	logger *logger.Logger
+}
+
+// NewStore creates a new state store rooted at dir
+func NewStore(dir string, log *logger.Logger) *Store {
+	return &Store{
+		dir:    dir,
+		logger: log,
+	}
+}
+
+// Enabled reports whether documents are persisted to disk
+func (s *Store) Enabled() bool {
+	return s != nil && s.dir != ""
+}
+
+// Load reads the named document into target. A disabled store or a missing document leaves target untouched.
+func (s *Store) Load(name string, target interface{}) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.logger.WithField("document", name).Debug("No persisted state found, starting fresh")
+			return nil
+		}
+		return fmt.Errorf("failed to read state %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to parse state %s: %w", name, err)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"document":   name,
+		"size_bytes": len(data),
+	}).Debug("Loaded persisted state")
+
+	return nil
+}
+
+// Save writes the named document atomically (temp file and rename) so a crash never leaves a truncated file
+func (s *Store) Save(name string, value interface{}) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state %s: %w", name, err)
+	}
+
+	tmpFile, err := os.CreateTemp(s.dir, "."+name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	tmpName := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write state %s: %w", name, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write state %s: %w", name, err)
+	}
+
+	if err := os.Rename(tmpName, s.path(name)); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace state %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// path returns the on-disk location of a named document
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
@@ -0,0 +1,204 @@
+package transfer
+
+import (
+	"crypto/md5" // #nosec G501 -- used for integrity checks only, not security
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/state"
+)
+
+// checksumCacheDocument is the state store document name for cached file hashes
+const checksumCacheDocument = "checksums"
+
+// checksumAlgorithm identifies the hash used for integrity verification
+type checksumAlgorithm string
+
+const (
+	checksumSHA256 checksumAlgorithm = "sha256"
+	checksumMD5    checksumAlgorithm = "md5"
+	checksumXXH64  checksumAlgorithm = "xxh64" // Non-cryptographic, much faster to compute on large media files
+)
+
+// newHash returns a fresh local hasher for the algorithm
+func (a checksumAlgorithm) newHash() (hash.Hash, error) {
+	switch a {
+	case checksumSHA256:
+		return sha256.New(), nil
+	case checksumMD5:
+		return md5.New(), nil // #nosec G401 -- integrity check only
+	case checksumXXH64:
+		return xxhash.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %s", a)
+	}
+}
+
+// remoteCommands returns the remote hashing commands to try in order (GNU coreutils, BSD/macOS, OpenSSL;
+// xxHash's own tools for xxh64)
+func (a checksumAlgorithm) remoteCommands() []string {
+	switch a {
+	case checksumSHA256:
+		return []string{"sha256sum", "shasum -a 256", "openssl dgst -sha256 -r"}
+	case checksumMD5:
+		return []string{"md5sum", "md5 -r", "openssl dgst -md5 -r"}
+	case checksumXXH64:
+		return []string{"xxh64sum", "xxhsum -H1"}
+	default:
+		return nil
+	}
+}
+
+// checksumEntry is a cached hash, valid only while the file keeps the same size and modification time
+type checksumEntry struct {
+	Size      int64             `json:"size"`
+	ModTime   int64             `json:"modTime"`
+	Algorithm checksumAlgorithm `json:"algorithm"`
+	Hash      string            `json:"hash"`
+}
+
+// checksumVerifier compares source and destination file hashes, caching them by size and mtime
+type checksumVerifier struct {
+	algorithm checksumAlgorithm
+	sourceOps fileOperations
+	destOps   fileOperations
+	store     *state.Store
+	logger    *logger.Logger
+
+	mu      sync.Mutex
+	Entries map[string]*checksumEntry `json:"entries"` // Keyed by "source:<path>" or "dest:<path>"
+}
+
+// newChecksumVerifier creates a checksum verifier and loads previously cached hashes
+func newChecksumVerifier(algorithm checksumAlgorithm, sourceOps, destOps fileOperations, store *state.Store, log *logger.Logger) *checksumVerifier {
+	verifier := &checksumVerifier{
+		algorithm: algorithm,
+		sourceOps: sourceOps,
+		destOps:   destOps,
+		store:     store,
+		logger:    log,
+		Entries:   make(map[string]*checksumEntry),
+	}
+
+	if err := store.Load(checksumCacheDocument, verifier); err != nil {
+		log.WithError(err).Warn("Failed to load checksum cache, hashes will be recomputed")
+	}
+	if verifier.Entries == nil {
+		verifier.Entries = make(map[string]*checksumEntry)
+	}
+
+	return verifier
+}
+
+// Verify reports whether the destination file has the same content hash as the source file
+func (v *checksumVerifier) Verify(sourcePath, destPath string) (bool, error) {
+	sourceHash, err := v.checksum(v.sourceOps, "source:", sourcePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash source file: %w", err)
+	}
+
+	destHash, err := v.checksum(v.destOps, "dest:", destPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash destination file: %w", err)
+	}
+
+	matches := sourceHash == destHash
+	v.logger.WithFields(map[string]interface{}{
+		"source_path": sourcePath,
+		"dest_path":   destPath,
+		"algorithm":   string(v.algorithm),
+		"source_hash": sourceHash,
+		"dest_hash":   destHash,
+		"matches":     matches,
+	}).Debug("Compared file checksums")
+
+	return matches, nil
+}
+
+// Invalidate drops the cached hash of a destination file (e.g. after it was deleted or rewritten)
+func (v *checksumVerifier) Invalidate(destPath string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.Entries, "dest:"+destPath)
+}
+
+// Save persists the checksum cache
+func (v *checksumVerifier) Save() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.store.Save(checksumCacheDocument, v)
+}
+
+// checksum hashes a file through fileOps (hashing locally or on the remote host), reusing the cached hash
+// when size and mtime are unchanged
+func (v *checksumVerifier) checksum(fileOps fileOperations, keyPrefix, path string) (string, error) {
+	info, err := fileOps.StatFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	key := keyPrefix + path
+	if cached := v.cached(key, info.Size, info.ModTime); cached != "" {
+		return cached, nil
+	}
+
+	sum, err := fileOps.ComputeChecksum(path, v.algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	v.remember(key, info.Size, info.ModTime, sum)
+	return sum, nil
+}
+
+// hashLocalFile computes the hex digest of a local file
+func hashLocalFile(path string, algorithm checksumAlgorithm) (string, error) {
+	hasher, err := algorithm.newHash()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// cached returns the cached hash for key if it is still valid for the given size and mtime
+func (v *checksumVerifier) cached(key string, size, modTime int64) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, exists := v.Entries[key]
+	if !exists || entry.Size != size || entry.ModTime != modTime || entry.Algorithm != v.algorithm {
+		return ""
+	}
+	return entry.Hash
+}
+
+// remember stores a computed hash in the cache
+func (v *checksumVerifier) remember(key string, size, modTime int64, sum string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.Entries[key] = &checksumEntry{
+		Size:      size,
+		ModTime:   modTime,
+		Algorithm: v.algorithm,
+		Hash:      sum,
+	}
+}
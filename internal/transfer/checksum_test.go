@@ -0,0 +1,40 @@
+package transfer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashLocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.txt")
+	if err := os.WriteFile(path, []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		algorithm checksumAlgorithm
+		want      string
+	}{
+		{checksumSHA256, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{checksumMD5, "900150983cd24fb0d6963f7d28e17f72"},
+		// Matches the output of `xxh64sum`/`xxhsum -H1`
+		{checksumXXH64, "44bc2cf5ad770999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.algorithm), func(t *testing.T) {
+			got, err := hashLocalFile(path, tt.algorithm)
+			if err != nil {
+				t.Fatalf("hashLocalFile() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("hashLocalFile(%s) = %s, want %s", tt.algorithm, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := hashLocalFile(path, "crc32"); err == nil {
+		t.Error("hashLocalFile() with an unsupported algorithm succeeded")
+	}
+}
@@ -0,0 +1,65 @@
+package transfer
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+
+	"github.com/pkg/sftp"
+)
+
+var (
+	// ErrRemoteNotFound reports that a destination file or directory does not exist
+	ErrRemoteNotFound = errors.New("remote path not found")
+	// ErrConnection reports that the destination could not be reached or the connection was lost
+	ErrConnection = errors.New("remote connection failed")
+)
+
+// RemoteError describes a failed destination file operation. It matches ErrRemoteNotFound or
+// ErrConnection with errors.Is when the failure falls into one of those classes.
+type RemoteError struct {
+	Op   string // Operation name, e.g. "stat" or "remove"
+	Path string
+	Kind error // ErrRemoteNotFound, ErrConnection or nil
+	Err  error // Underlying error
+}
+
+// Error returns a readable description of the failure
+func (e *RemoteError) Error() string {
+	return e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap exposes both the error class and the underlying error to errors.Is and errors.As
+func (e *RemoteError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// newRemoteError classifies err from a file operation on path
+func newRemoteError(op, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	remoteErr := &RemoteError{Op: op, Path: path, Err: err}
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		remoteErr.Kind = ErrRemoteNotFound
+	case isConnectionError(err):
+		remoteErr.Kind = ErrConnection
+	}
+	return remoteErr
+}
+
+// isConnectionError reports whether err means the SSH/SFTP connection is unusable
+func isConnectionError(err error) bool {
+	if errors.Is(err, ErrConnection) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, sftp.ErrSSHFxConnectionLost) || errors.Is(err, sftp.ErrSSHFxNoConnection) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
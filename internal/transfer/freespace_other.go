@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package transfer
+
+import "errors"
+
+// freeSpace is not supported on this platform; the capacity preflight is skipped
+func freeSpace(path string) (int64, error) {
+	return 0, errors.New("free space detection is not supported on this platform")
+}
@@ -0,0 +1,186 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+)
+
+// linkMode selects how a destination file is created from a source on the same filesystem
+type linkMode string
+
+const (
+	linkOff      linkMode = "off"
+	linkHardlink linkMode = "hardlink"
+	linkReflink  linkMode = "reflink"
+	linkAuto     linkMode = "auto" // Reflink first (independent copy-on-write file), then hardlink
+)
+
+// errCrossDevice reports that source and destination are on different filesystems, so a copy is needed
+var errCrossDevice = errors.New("source and destination are on different devices")
+
+// attempts returns the concrete link kinds to try in order
+func (m linkMode) attempts() []linkMode {
+	switch m {
+	case linkHardlink, linkReflink:
+		return []linkMode{m}
+	case linkAuto:
+		return []linkMode{linkReflink, linkHardlink}
+	default:
+		return nil
+	}
+}
+
+// LinkFile hardlinks or reflinks sourcePath to destPath through a hidden temp name on the local filesystem
+func (l *localFileOps) LinkFile(sourcePath, destPath string, mode linkMode) error {
+	tempPath := tempPathFor(destPath)
+	os.Remove(tempPath)
+
+	var err error
+	switch mode {
+	case linkHardlink:
+		err = os.Link(sourcePath, tempPath)
+	case linkReflink:
+		err = reflinkLocal(sourcePath, tempPath)
+	default:
+		return fmt.Errorf("unsupported link mode: %s", mode)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		if errors.Is(err, syscall.EXDEV) {
+			return fmt.Errorf("%w: %v", errCrossDevice, err)
+		}
+		return err
+	}
+
+	if err := os.Rename(tempPath, destPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to move link into place: %w", err)
+	}
+	return nil
+}
+
+// reflinkLocal creates tempPath as a copy-on-write clone of sourcePath, keeping the modification time
+func reflinkLocal(sourcePath, tempPath string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	dest, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := cloneFile(dest, source); err != nil {
+		dest.Close()
+		return err
+	}
+	if err := dest.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(tempPath, info.ModTime(), info.ModTime())
+}
+
+// LinkFile hardlinks or reflinks a file that already exists on the destination host (GNU cp is needed for
+// reflinks). Failures are reported on stdout so cross-device errors can be told apart from other failures.
+func (s *sshClient) LinkFile(sourcePath, destPath string, mode linkMode) error {
+	tempPath := tempPathFor(destPath)
+
+	var linkCmd string
+	switch mode {
+	case linkHardlink:
+		linkCmd = fmt.Sprintf("ln %s %s", shellQuote(sourcePath), shellQuote(tempPath))
+	case linkReflink:
+		linkCmd = fmt.Sprintf("cp --reflink=always --preserve=timestamps %s %s", shellQuote(sourcePath), shellQuote(tempPath))
+	default:
+		return fmt.Errorf("unsupported link mode: %s", mode)
+	}
+
+	cmd := fmt.Sprintf("rm -f %s; if out=$(%s 2>&1); then mv -f %s %s && echo linked; else rm -f %s; echo \"$out\"; fi",
+		shellQuote(tempPath), linkCmd, shellQuote(tempPath), shellQuote(destPath), shellQuote(tempPath))
+
+	output, err := s.executeCommand(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", sourcePath, destPath, err)
+	}
+
+	message := strings.TrimSpace(string(output))
+	if message == "linked" {
+		s.logger.WithFields(map[string]interface{}{
+			"source_path": sourcePath,
+			"dest_path":   destPath,
+			"link_mode":   string(mode),
+		}).Debug("Remote file linked successfully")
+		return nil
+	}
+	if strings.Contains(strings.ToLower(message), "cross-device") {
+		return fmt.Errorf("%w: %s", errCrossDevice, message)
+	}
+	return fmt.Errorf("failed to link %s to %s: %s", sourcePath, destPath, message)
+}
+
+// linkSourcePath returns the path of the source file as seen by the destination's file operations.
+// Locally mounted destinations see the container path; remote destinations mirror DEST_ROOT_DIR under
+// LINK_SOURCE_ROOT.
+func (t *transferClient) linkSourcePath(sourcePath, destPath string) (string, error) {
+	if t.method == TransferMethodLocal && t.linkSourceRoot == "" {
+		return sourcePath, nil
+	}
+
+	destRoot := strings.TrimSuffix(t.destRootDir, "/")
+	if !strings.HasPrefix(destPath, destRoot+"/") {
+		return "", fmt.Errorf("destination path %s is not under DEST_ROOT_DIR %s", destPath, t.destRootDir)
+	}
+	return path.Join(t.linkSourceRoot, strings.TrimPrefix(destPath, destRoot+"/")), nil
+}
+
+// tryLink creates destPath as a link to the source instead of copying it. It returns false when the
+// caller should fall back to a regular copy (different devices, unsupported filesystem, missing source).
+func (t *transferClient) tryLink(sourcePath, destPath string, size int64) bool {
+	linkSource, err := t.linkSourcePath(sourcePath, destPath)
+	if err != nil {
+		t.logger.WithError(err).WithField("dest_path", destPath).Debug("Cannot map source path for linking, copying instead")
+		return false
+	}
+
+	for _, mode := range t.linkMode.attempts() {
+		err = t.fileOps.LinkFile(linkSource, destPath, mode)
+		if err == nil {
+			t.logger.WithFields(map[string]interface{}{
+				"event":       "file_linked",
+				"source_path": linkSource,
+				"dest_path":   destPath,
+				"link_mode":   string(mode),
+				"size_mb":     math.Round(float64(size)/(1024*1024)*10) / 10,
+			}).Info("File linked instead of copied")
+			t.progress.SkipFile(size)
+			return true
+		}
+
+		fields := map[string]interface{}{
+			"source_path": linkSource,
+			"dest_path":   destPath,
+			"link_mode":   string(mode),
+		}
+		if errors.Is(err, errCrossDevice) {
+			// A different device fails for every link kind, no need to try the next one
+			t.logger.WithFields(fields).Debug("Source and destination are on different devices, copying instead")
+			return false
+		}
+		t.logger.WithError(err).WithFields(fields).Debug("Link attempt failed")
+	}
+
+	t.logger.WithError(err).WithField("dest_path", destPath).Warn("Could not link file, copying instead")
+	return false
+}
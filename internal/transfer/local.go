@@ -0,0 +1,331 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/pkg/types"
+)
+
+// localCopyChunkSize is how much is copied between progress updates. Chunks are copied file-to-file,
+// so the kernel fast path (copy_file_range) is kept.
+const localCopyChunkSize = 32 * 1024 * 1024
+
+// localFileOps implements fileOperations against a locally mounted destination (NFS/SMB/bind mount)
+type localFileOps struct {
+	logger *logger.Logger
+}
+
+// newLocalFileOps creates file operations for a locally mounted destination
+func newLocalFileOps(log *logger.Logger) fileOperations {
+	return &localFileOps{logger: log}
+}
+
+// GetFileSize returns the size of a local file
+func (l *localFileOps) GetFileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, newRemoteError("stat", path, err)
+	}
+	return info.Size(), nil
+}
+
+// DeleteFile deletes a local file (a missing file is not an error, matching rm -f)
+func (l *localFileOps) DeleteFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ListDirectoryContents recursively lists all files below rootPath
+func (l *localFileOps) ListDirectoryContents(rootPath string) ([]string, error) {
+	sizes, err := l.ListFileSizes(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(sizes))
+	for path := range sizes {
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// ListFileSizes recursively lists all files below rootPath with their sizes (a missing root yields an empty map)
+func (l *localFileOps) ListFileSizes(rootPath string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+
+	err := filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == rootPath && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		sizes[path] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files under %s: %w", rootPath, err)
+	}
+
+	l.logger.WithFields(map[string]interface{}{
+		"root_path":  rootPath,
+		"file_count": len(sizes),
+	}).Debug("Listed local destination files")
+
+	return sizes, nil
+}
+
+// ListFiles returns the names of the regular files directly inside a local directory.
+// Symlinks are followed, so sidecars linked into the directory are listed too; broken links are left out.
+func (l *localFileOps) ListFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, newRemoteError("readdir", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(dir, entry.Name())); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+		} else if !entry.Type().IsRegular() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// CreateDirectory creates a local directory and its parents
+func (l *localFileOps) CreateDirectory(path string) error {
+	return os.MkdirAll(path, 0o755)
+}
+
+// RenameFile moves a local file into place, replacing any existing file at newPath
+func (l *localFileOps) RenameFile(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
+// GetFreeSpace returns the bytes available on the filesystem holding path (or its nearest existing parent)
+func (l *localFileOps) GetFreeSpace(path string) (int64, error) {
+	current := path
+	for {
+		if _, err := os.Stat(current); err == nil {
+			return freeSpace(current)
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return 0, fmt.Errorf("no existing directory found for %s", path)
+		}
+		current = parent
+	}
+}
+
+// StatFile returns the size and modification time of a local file
+func (l *localFileOps) StatFile(path string) (*remoteFileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, newRemoteError("stat", path, err)
+	}
+	return &remoteFileInfo{Size: info.Size(), ModTime: info.ModTime().Unix()}, nil
+}
+
+// ComputeChecksum hashes a local file
+func (l *localFileOps) ComputeChecksum(path string, algorithm checksumAlgorithm) (string, error) {
+	return hashLocalFile(path, algorithm)
+}
+
+// Close is a no-op for local file operations
+func (l *localFileOps) Close() error {
+	return nil
+}
+
+// LocalTransfer copies files to a locally mounted destination
+type LocalTransfer struct {
+	fileOps         fileOperations
+	resumeTransfers bool
+	bandwidthLimit  int64 // Bytes per second, 0 = unlimited
+	progress        *ProgressTracker
+	logger          *logger.Logger
+}
+
+// newLocalTransfer creates a new local transfer instance (package-private)
+func newLocalTransfer(cfg *config.Config, fileOps fileOperations, progress *ProgressTracker, log *logger.Logger) (*LocalTransfer, error) {
+	return &LocalTransfer{
+		fileOps:         fileOps,
+		resumeTransfers: cfg.Transfer.ResumeTransfers,
+		progress:        progress,
+		logger:          log,
+	}, nil
+}
+
+// doTransferFile copies a file to a hidden temp name next to the target, preserves the modification
+// time and renames it into place. With resume enabled, an interrupted temp file is continued.
+func (l *LocalTransfer) doTransferFile(sourcePath, destPath string) error {
+	// Directory creation is handled by the common transferrer before calling this method
+	tempPath := tempPathFor(destPath)
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer source.Close()
+
+	sourceInfo, err := source.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat source file: %w", err)
+	}
+
+	l.progress.StartFile(sourcePath, destPath, sourceInfo.Size())
+	transferred := false
+	defer func() { l.progress.EndFile(transferred) }()
+
+	infoPath := resumeInfoPathFor(destPath)
+	offset := l.resumeOffset(tempPath, infoPath, sourceInfo)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	} else if l.resumeTransfers {
+		// Record which source version the temp file holds, so a later resume can tell if the source changed
+		if err := os.WriteFile(infoPath, []byte(resumeInfo(sourceInfo)), 0o644); err != nil {
+			l.logger.WithError(err).WithField("dest_path", destPath).Debug("Failed to record source of temp file, it will not be resumed")
+		}
+	}
+
+	dest, err := os.OpenFile(tempPath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	if err := l.copyContents(dest, source, offset, sourceInfo.Size()); err != nil {
+		dest.Close()
+		if !l.resumeTransfers {
+			os.Remove(tempPath)
+		}
+		return err
+	}
+
+	if err := dest.Sync(); err != nil {
+		dest.Close()
+		return fmt.Errorf("failed to flush temp file: %w", err)
+	}
+	if err := dest.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Chtimes(tempPath, sourceInfo.ModTime(), sourceInfo.ModTime()); err != nil {
+		l.logger.WithError(err).WithField("dest_path", destPath).Debug("Failed to preserve modification time")
+	}
+
+	if err := os.Rename(tempPath, destPath); err != nil {
+		return fmt.Errorf("failed to move temp file into place: %w", err)
+	}
+	if l.resumeTransfers {
+		if err := os.Remove(infoPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			l.logger.WithError(err).WithField("dest_path", destPath).Debug("Failed to remove source record of temp file")
+		}
+	}
+
+	transferred = true
+	return nil
+}
+
+// resumeInfo formats the source size and modification time recorded for a resumable temp file
+func resumeInfo(sourceInfo os.FileInfo) string {
+	return fmt.Sprintf("%d %d", sourceInfo.Size(), sourceInfo.ModTime().UnixNano())
+}
+
+// resumeOffset returns how many bytes of an interrupted temp file can be kept. The temp file is only
+// continued when it was copied from the same source version, otherwise the result would be spliced.
+func (l *LocalTransfer) resumeOffset(tempPath, infoPath string, sourceInfo os.FileInfo) int64 {
+	if !l.resumeTransfers {
+		return 0
+	}
+
+	info, err := os.Stat(tempPath)
+	if err != nil || info.Size() == 0 || info.Size() >= sourceInfo.Size() {
+		return 0
+	}
+
+	recorded, err := os.ReadFile(infoPath)
+	if err != nil || string(recorded) != resumeInfo(sourceInfo) {
+		l.logger.WithField("temp_path", tempPath).Info("Source changed since the interrupted transfer, restarting from the beginning")
+		return 0
+	}
+
+	l.logger.LogTransferResumed(tempPath, info.Size(), sourceInfo.Size())
+	return info.Size()
+}
+
+// copyContents copies source (from offset) to dest. A fresh, unthrottled copy first tries a reflink clone.
+func (l *LocalTransfer) copyContents(dest, source *os.File, offset, size int64) error {
+	if offset == 0 && l.bandwidthLimit == 0 {
+		if err := cloneFile(dest, source); err == nil {
+			l.progress.UpdateFile(size)
+			l.logger.WithField("dest_path", dest.Name()).Debug("Cloned file with reflink")
+			return nil
+		}
+	}
+
+	if offset > 0 {
+		if _, err := source.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek source file for resume: %w", err)
+		}
+	}
+
+	var writer io.Writer = dest
+	if l.bandwidthLimit > 0 {
+		writer = NewThrottledWriter(dest, l.bandwidthLimit, l.logger)
+	}
+
+	written := offset
+	for {
+		n, err := io.CopyN(writer, source, localCopyChunkSize)
+		written += n
+		l.progress.UpdateFile(written)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to copy file contents: %w", err)
+		}
+	}
+}
+
+// doTransferFiles copies multiple files individually
+func (l *LocalTransfer) doTransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error) {
+	results := make([]types.FileTransferResult, 0, len(files))
+	for _, file := range files {
+		err := l.doTransferFile(file.SourcePath, file.DestPath)
+		results = append(results, newFileTransferResult(file, err == nil, err))
+	}
+	return results, nil
+}
+
+// batchesFiles reports that local copies need the common size check (stat calls are cheap locally)
+func (l *LocalTransfer) batchesFiles() bool {
+	return false
+}
+
+// setBandwidthLimit sets the cap applied to subsequent copies
+func (l *LocalTransfer) setBandwidthLimit(bytesPerSecond int64) {
+	l.bandwidthLimit = bytesPerSecond
+}
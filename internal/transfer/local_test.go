@@ -0,0 +1,107 @@
+package transfer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+)
+
+func TestLocalListFilesFollowsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	shared := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "Movie.mkv"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(shared, "Movie.en.srt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "Extras"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"Movie.en.srt": filepath.Join(shared, "Movie.en.srt"), // Symlinked sidecar
+		"Linked":       shared,                                // Symlinked directory
+		"Broken.srt":   filepath.Join(shared, "missing.srt"),  // Broken link
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	names, err := newLocalFileOps(logger.New("error")).ListFiles(dir)
+	if err != nil {
+		t.Fatalf("ListFiles() failed: %v", err)
+	}
+	sort.Strings(names)
+	if want := []string{"Movie.en.srt", "Movie.mkv"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListFiles() = %v, want %v", names, want)
+	}
+}
+
+func TestLocalTransferResume(t *testing.T) {
+	tests := []struct {
+		name          string
+		partial       string
+		sourceChanged bool
+	}{
+		{name: "partial copy of the same source is continued", partial: "first half "},
+		{name: "partial copy of a replaced source is restarted", partial: "old content", sourceChanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sourcePath := filepath.Join(dir, "source.mkv")
+			destPath := filepath.Join(dir, "dest", "Movie.mkv")
+			content := "first half second half"
+			if err := os.WriteFile(sourcePath, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Mkdir(filepath.Dir(destPath), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			// Leave the state of an interrupted transfer: the partial temp file and its source record
+			sourceInfo, err := os.Stat(sourcePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(tempPathFor(destPath), []byte(tt.partial), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(resumeInfoPathFor(destPath), []byte(resumeInfo(sourceInfo)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.sourceChanged {
+				modTime := sourceInfo.ModTime().Add(time.Hour)
+				if err := os.Chtimes(sourcePath, modTime, modTime); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			log := logger.New("error")
+			local := &LocalTransfer{fileOps: newLocalFileOps(log), resumeTransfers: true, progress: NewProgressTracker(0, log), logger: log}
+			if err := local.doTransferFile(sourcePath, destPath); err != nil {
+				t.Fatalf("doTransferFile() failed: %v", err)
+			}
+
+			got, err := os.ReadFile(destPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != content {
+				t.Errorf("destination = %q, want %q", got, content)
+			}
+			if _, err := os.Stat(resumeInfoPathFor(destPath)); !os.IsNotExist(err) {
+				t.Errorf("source record was not removed after the transfer: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package transfer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+)
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantBytes int64
+		wantOK    bool
+	}{
+		{"  1,234,567  45%   10.52MB/s    0:01:23", 1234567, true},
+		{"      32,768 100%   31.25MB/s    0:00:00 (xfr#1, to-chk=0/1)", 32768, true},
+		{"0   0%    0.00kB/s    0:00:00", 0, true},
+		{"sending incremental file list", 0, false},
+		{">f+++++++++ Movie.mkv", 0, false},
+		{"many 45%", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		gotBytes, gotOK := parseProgressLine(tt.line)
+		if gotBytes != tt.wantBytes || gotOK != tt.wantOK {
+			t.Errorf("parseProgressLine(%q) = (%d, %t), want (%d, %t)", tt.line, gotBytes, gotOK, tt.wantBytes, tt.wantOK)
+		}
+	}
+}
+
+func TestParseItemizeLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantName string
+		wantOK   bool
+	}{
+		{">f+++++++++ Show/Season 01/Show S01E01.mkv", "Show S01E01.mkv", true},
+		{"<f.st...... Movie (2020).mkv", "Movie (2020).mkv", true},
+		{">f+++++++++ Caf\\#303\\#251 Society.mkv", "Café Society.mkv", true},
+		{"cd+++++++++ Show/Season 01/", "", false},
+		{".d..t...... ./", "", false},
+		{"*deleting   Old.mkv", "", false},
+		{">f+++++++++", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		gotName, gotOK := parseItemizeLine(tt.line)
+		if gotName != tt.wantName || gotOK != tt.wantOK {
+			t.Errorf("parseItemizeLine(%q) = (%q, %t), want (%q, %t)", tt.line, gotName, gotOK, tt.wantName, tt.wantOK)
+		}
+	}
+}
+
+func TestUnescapeRsyncName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Movie.mkv", "Movie.mkv"},
+		{"Caf\\#303\\#251.mkv", "Café.mkv"},
+		{"tab\\#011name.srt", "tab\tname.srt"},
+		{"not octal \\#9x9.mkv", "not octal \\#9x9.mkv"},
+		{"too short \\#30", "too short \\#30"},
+		{"back\\slash.mkv", "back\\slash.mkv"},
+	}
+
+	for _, tt := range tests {
+		if got := unescapeRsyncName(tt.name); got != tt.want {
+			t.Errorf("unescapeRsyncName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseDfAvailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:   "posix output",
+			output: "Filesystem     1024-blocks      Used Available Capacity Mounted on\n/dev/sda1        976762584 500000000 476762584      52% /mnt/media\n",
+			want:   476762584 * 1024,
+		},
+		{
+			name:   "mount point with spaces",
+			output: "Filesystem 1024-blocks Used Available Capacity Mounted on\n//nas/media 2000 500 1500 25% /mnt/my media\n",
+			want:   1500 * 1024,
+		},
+		{
+			name:    "header only",
+			output:  "Filesystem 1024-blocks Used Available Capacity Mounted on\n",
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric available space",
+			output:  "Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/sda1 2000 500 - 25% /mnt\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty output",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDfAvailable(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDfAvailable() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDfAvailable() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJumpHostArgs(t *testing.T) {
+	const sshOptions = "ssh -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null"
+
+	tests := []struct {
+		name      string
+		jumpHosts []config.JumpHost
+		want      []string
+	}{
+		{
+			name: "no jump hosts",
+		},
+		{
+			name: "default identities use ProxyJump",
+			jumpHosts: []config.JumpHost{
+				{User: "admin", Host: "bastion.example", Port: "22"},
+				{User: "ops", Host: "10.0.0.5", Port: "2200"},
+			},
+			want: []string{"-J", "admin@bastion.example:22,ops@10.0.0.5:2200"},
+		},
+		{
+			name: "key path with spaces is quoted",
+			jumpHosts: []config.JumpHost{
+				{User: "admin", Host: "bastion.example", Port: "22", KeyPath: "/keys/my key"},
+			},
+			want: []string{"-o", "ProxyCommand=" + sshOptions + " -p 22 -i '/keys/my key' -W nas.local:2222 'admin@bastion.example'"},
+		},
+		{
+			name: "password is quoted and percent signs are escaped",
+			jumpHosts: []config.JumpHost{
+				{User: "admin", Host: "bastion.example", Port: "22", Password: "it's 100%"},
+			},
+			want: []string{"-o", "ProxyCommand=sshpass -p 'it'\"'\"'s 100%%' " + sshOptions + " -p 22 -W nas.local:2222 'admin@bastion.example'"},
+		},
+		{
+			name: "chained hops nest their proxy commands",
+			jumpHosts: []config.JumpHost{
+				{User: "a", Host: "hop1", Port: "22", Password: "p%w"},
+				{User: "b", Host: "hop2", Port: "2200"},
+			},
+			// The inner command is escaped once for hop2's ssh and once more for the outer ssh
+			want: []string{"-o", "ProxyCommand=" + sshOptions + " -p 2200 -o " +
+				"'ProxyCommand=sshpass -p '\"'\"'p%%%%w'\"'\"' " + sshOptions + " -p 22 -W hop2:2200 '\"'\"'a@hop1'\"'\"''" +
+				" -W nas.local:2222 'b@hop2'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jumpHostArgs(tt.jumpHosts, "nas.local", "2222"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jumpHostArgs() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
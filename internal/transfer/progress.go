@@ -0,0 +1,229 @@
+package transfer
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+)
+
+// FileProgress describes the file currently being transferred
+type FileProgress struct {
+	SourcePath string    `json:"sourcePath"`
+	DestPath   string    `json:"destPath"`
+	BytesDone  int64     `json:"bytesDone"`
+	TotalBytes int64     `json:"totalBytes"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// ProgressSnapshot is a point-in-time view of transfer progress for the current file and cycle
+type ProgressSnapshot struct {
+	Active           bool          `json:"active"`
+	CurrentFile      *FileProgress `json:"currentFile,omitempty"`
+	FileETA          time.Duration `json:"fileEta"`
+	CycleBytesDone   int64         `json:"cycleBytesDone"`  // Bytes of processed files (transferred or already up to date)
+	CycleTotalBytes  int64         `json:"cycleTotalBytes"` // Bytes planned for this cycle
+	BytesTransferred int64         `json:"bytesTransferred"`
+	FilesDone        int           `json:"filesDone"`
+	FilesTotal       int           `json:"filesTotal"`
+	BytesPerSecond   float64       `json:"bytesPerSecond"`
+	CycleETA         time.Duration `json:"cycleEta"`
+	CycleStartedAt   time.Time     `json:"cycleStartedAt"`
+}
+
+// ProgressTracker collects byte-level progress from the transfer implementations, computes
+// throughput and ETA, and logs it at a fixed interval. It is safe for concurrent use.
+type ProgressTracker struct {
+	logger      *logger.Logger
+	logInterval time.Duration
+
+	mu               sync.Mutex
+	active           bool
+	cycleStartedAt   time.Time
+	cycleTotalBytes  int64
+	cycleDoneBytes   int64
+	bytesTransferred int64
+	filesTotal       int
+	filesDone        int
+	current          *FileProgress
+	lastLog          time.Time
+}
+
+// NewProgressTracker creates a progress tracker that logs every logInterval (0 disables periodic logging)
+func NewProgressTracker(logInterval time.Duration, log *logger.Logger) *ProgressTracker {
+	return &ProgressTracker{
+		logger:      log,
+		logInterval: logInterval,
+	}
+}
+
+// StartCycle resets the tracker for a new cycle with the planned number of files and bytes
+func (p *ProgressTracker) StartCycle(totalFiles int, totalBytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active = true
+	p.cycleStartedAt = time.Now()
+	p.cycleTotalBytes = totalBytes
+	p.cycleDoneBytes = 0
+	p.bytesTransferred = 0
+	p.filesTotal = totalFiles
+	p.filesDone = 0
+	p.current = nil
+	p.lastLog = time.Now()
+}
+
+// FinishCycle marks the cycle as complete
+func (p *ProgressTracker) FinishCycle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active = false
+	p.current = nil
+}
+
+// StartFile marks the beginning of an actual data transfer
+func (p *ProgressTracker) StartFile(sourcePath, destPath string, totalBytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = &FileProgress{
+		SourcePath: sourcePath,
+		DestPath:   destPath,
+		TotalBytes: totalBytes,
+		StartedAt:  time.Now(),
+	}
+}
+
+// UpdateFile records the number of bytes of the current file written so far
+func (p *ProgressTracker) UpdateFile(bytesDone int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current == nil {
+		return
+	}
+	p.current.BytesDone = bytesDone
+
+	if p.logInterval > 0 && time.Since(p.lastLog) >= p.logInterval {
+		p.lastLog = time.Now()
+		p.logProgress(p.snapshot())
+	}
+}
+
+// EndFile completes the current file; transferred is false when the transfer failed
+func (p *ProgressTracker) EndFile(transferred bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current == nil {
+		return
+	}
+
+	if transferred {
+		p.bytesTransferred += p.current.TotalBytes
+	} else {
+		p.bytesTransferred += p.current.BytesDone
+	}
+	p.cycleDoneBytes += p.current.TotalBytes
+	p.filesDone++
+	p.current = nil
+}
+
+// SkipFile counts a file that needed no transfer (already up to date) as processed
+func (p *ProgressTracker) SkipFile(totalBytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cycleDoneBytes += totalBytes
+	p.filesDone++
+}
+
+// Snapshot returns the current progress
+func (p *ProgressTracker) Snapshot() ProgressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.snapshot()
+}
+
+// snapshot builds a snapshot; the caller must hold the lock
+func (p *ProgressTracker) snapshot() ProgressSnapshot {
+	snapshot := ProgressSnapshot{
+		Active:           p.active,
+		CycleBytesDone:   p.cycleDoneBytes,
+		CycleTotalBytes:  p.cycleTotalBytes,
+		BytesTransferred: p.bytesTransferred,
+		FilesDone:        p.filesDone,
+		FilesTotal:       p.filesTotal,
+		CycleStartedAt:   p.cycleStartedAt,
+	}
+
+	var currentDone int64
+	if p.current != nil {
+		current := *p.current
+		snapshot.CurrentFile = &current
+		currentDone = current.BytesDone
+	}
+
+	// Throughput counts only bytes that actually crossed the wire
+	if elapsed := time.Since(p.cycleStartedAt).Seconds(); p.active && elapsed > 0 {
+		snapshot.BytesPerSecond = float64(p.bytesTransferred+currentDone) / elapsed
+	}
+
+	if snapshot.BytesPerSecond > 0 {
+		if p.current != nil {
+			remaining := p.current.TotalBytes - currentDone
+			snapshot.FileETA = etaFor(remaining, snapshot.BytesPerSecond)
+		}
+		remaining := p.cycleTotalBytes - p.cycleDoneBytes - currentDone
+		snapshot.CycleETA = etaFor(remaining, snapshot.BytesPerSecond)
+	}
+
+	return snapshot
+}
+
+// logProgress writes a progress line for the snapshot
+func (p *ProgressTracker) logProgress(snapshot ProgressSnapshot) {
+	fields := map[string]interface{}{
+		"event":         "transfer_progress",
+		"files":         fmt.Sprintf("%d/%d", snapshot.FilesDone, snapshot.FilesTotal),
+		"cycle_pct":     percent(snapshot.CycleBytesDone+currentBytes(snapshot), snapshot.CycleTotalBytes),
+		"rate_mbps":     math.Round(snapshot.BytesPerSecond/(1024*1024)*10) / 10,
+		"cycle_eta_min": math.Round(snapshot.CycleETA.Minutes()*10) / 10,
+	}
+	if snapshot.CurrentFile != nil {
+		fields["file"] = filepath.Base(snapshot.CurrentFile.SourcePath)
+		fields["file_pct"] = percent(snapshot.CurrentFile.BytesDone, snapshot.CurrentFile.TotalBytes)
+		fields["file_eta_min"] = math.Round(snapshot.FileETA.Minutes()*10) / 10
+	}
+
+	p.logger.WithFields(fields).Info("Transfer progress")
+}
+
+// currentBytes returns the bytes done of the snapshot's current file
+func currentBytes(snapshot ProgressSnapshot) int64 {
+	if snapshot.CurrentFile == nil {
+		return 0
+	}
+	return snapshot.CurrentFile.BytesDone
+}
+
+// etaFor estimates the time to move the remaining bytes at the given rate
+func etaFor(remaining int64, bytesPerSecond float64) time.Duration {
+	if remaining <= 0 {
+		return 0
+	}
+	return time.Duration(float64(remaining) / bytesPerSecond * float64(time.Second))
+}
+
+// percent returns done/total as a percentage with one decimal
+func percent(done, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Min(100, math.Round(float64(done)/float64(total)*1000)/10)
+}
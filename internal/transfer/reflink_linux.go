@@ -0,0 +1,14 @@
+//go:build linux
+
+package transfer
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile makes dest a copy-on-write clone of source (btrfs, xfs and other reflink-capable filesystems)
+func cloneFile(dest, source *os.File) error {
+	return unix.IoctlFileClone(int(dest.Fd()), int(source.Fd()))
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package transfer
+
+import (
+	"errors"
+	"os"
+)
+
+// cloneFile is not supported on this platform; callers fall back to a regular copy
+func cloneFile(dest, source *os.File) error {
+	return errors.New("reflink cloning is not supported on this platform")
+}
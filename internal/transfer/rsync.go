@@ -2,10 +2,14 @@
 package transfer
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/nullable-eth/syncarr/internal/config"
@@ -16,21 +20,27 @@ import (
 // RsyncTransfer handles file transfers using rsync over SSH
 type RsyncTransfer struct {
 	sshConfig         *config.SSHConfig
-	serverConfig      *config.PlexServerConfig
 	sourceReplaceFrom string
 	sourceReplaceTo   string
 	destRootDir       string
 	logger            *logger.Logger
-	compressionLevel  int  // 0-9, 0=none, 6=default, 9=max
-	parallelStreams   int  // Number of parallel rsync streams
-	checksumSkip      bool // Skip checksum verification for speed
+	compressionLevel  int   // 0-9, 0=none, 6=default, 9=max
+	parallelStreams   int   // Number of parallel rsync streams
+	checksumSkip      bool  // Skip checksum verification for speed
+	resumeTransfers   bool  // Keep interrupted transfers in a hidden partial dir for resuming
+	bandwidthLimit    int64 // Bytes per second, 0 = unlimited
+	pull              bool  // Fetch from the source host into local paths instead of pushing to the destination
+	sourceOps         fileOperations
+	progress          *ProgressTracker
 }
 
 // newRsyncTransfer creates a new rsync transfer instance (package-private)
-func newRsyncTransfer(cfg *config.Config, log *logger.Logger) (*RsyncTransfer, error) {
+func newRsyncTransfer(cfg *config.Config, sourceOps fileOperations, progress *ProgressTracker, log *logger.Logger) (*RsyncTransfer, error) {
 	return &RsyncTransfer{
+		progress:          progress,
+		pull:              cfg.TransferDirection == TransferDirectionPull,
+		sourceOps:         sourceOps,
 		sshConfig:         &cfg.SSH,
-		serverConfig:      &cfg.Destination,
 		sourceReplaceFrom: cfg.SourceReplaceFrom,
 		sourceReplaceTo:   cfg.SourceReplaceTo,
 		destRootDir:       cfg.DestRootDir,
@@ -38,72 +48,355 @@ func newRsyncTransfer(cfg *config.Config, log *logger.Logger) (*RsyncTransfer, e
 		compressionLevel:  1,    // Light compression for speed vs bandwidth balance
 		parallelStreams:   4,    // Multiple parallel streams
 		checksumSkip:      true, // Skip checksums for max speed (trust network)
+		resumeTransfers:   cfg.Transfer.ResumeTransfers,
 	}, nil
 }
 
 // doTransferFile transfers a single file using rsync (internal implementation without common logic)
 func (r *RsyncTransfer) doTransferFile(sourcePath, destPath string) error {
+	_, err := r.transferSingle(sourcePath, destPath)
+	return err
+}
+
+// transferSingle runs one rsync for a single file and reports whether data was actually transferred
+func (r *RsyncTransfer) transferSingle(sourcePath, destPath string) (bool, error) {
 	// Directory creation is now handled by the common transferrer before calling this method
 
 	// Build rsync command with optimizations
 	args := r.buildRsyncArgs(sourcePath, destPath)
 
-	cmd := exec.Command("rsync", args...)
+	var size int64
+	if info, err := r.sourceOps.StatFile(sourcePath); err == nil {
+		size = info.Size
+	}
+	r.progress.StartFile(sourcePath, destPath, size)
 
-	// Capture output for debugging
-	output, err := cmd.CombinedOutput()
+	// Stream output for live progress while keeping it for debugging
+	output, err := r.runRsync(args, func(line string) {
+		if bytesDone, ok := parseProgressLine(line); ok {
+			r.progress.UpdateFile(bytesDone)
+		}
+	})
 	if err != nil {
+		r.progress.EndFile(false)
 		r.logger.WithFields(map[string]interface{}{
 			"source_path": sourcePath,
 			"dest_path":   destPath,
 			"rsync_args":  strings.Join(args, " "),
-			"output":      string(output),
+			"output":      output,
 		}).Error("Rsync command failed")
-		return fmt.Errorf("rsync failed: %w", err)
+		return false, fmt.Errorf("rsync failed: %w", err)
 	}
 
 	// Check if rsync actually transferred data or skipped the file
-	if r.isFileSkipped(string(output), sourcePath) {
+	if r.isFileSkipped(output, sourcePath) {
+		r.progress.EndFile(false)
 		r.logger.WithFields(map[string]interface{}{
 			"source_path": sourcePath,
 			"dest_path":   destPath,
 		}).Debug("File was skipped by rsync (already up-to-date)")
-		return nil // Not an error, just skipped
+		return false, nil // Not an error, just skipped
 	}
 
-	return nil
+	r.progress.EndFile(true)
+	return true, nil
+}
+
+// runRsync runs rsync, passing each output line (progress updates are separated by carriage
+// returns) to onLine as it arrives, and returns the complete output
+func (r *RsyncTransfer) runRsync(args []string, onLine func(line string)) (string, error) {
+	cmd := exec.Command("rsync", args...)
+
+	pipeReader, pipeWriter := io.Pipe()
+	cmd.Stdout = pipeWriter
+	cmd.Stderr = pipeWriter
+
+	var output strings.Builder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pipeReader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		scanner.Split(scanLinesOrCarriageReturns)
+		for scanner.Scan() {
+			line := scanner.Text()
+			output.WriteString(line)
+			output.WriteByte('\n')
+			onLine(line)
+		}
+		// Keep draining if the scanner stopped early so rsync never blocks on a full pipe
+		_, _ = io.Copy(io.Discard, pipeReader)
+	}()
+
+	err := cmd.Run()
+	pipeWriter.Close()
+	<-done
+
+	return output.String(), err
+}
+
+// scanLinesOrCarriageReturns is a bufio.SplitFunc that splits on \n and \r
+func scanLinesOrCarriageReturns(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// parseProgressLine extracts the bytes transferred so far from an rsync --progress line
+// such as "  1,234,567  45%   10.52MB/s    0:01:23"
+func parseProgressLine(line string) (int64, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.HasSuffix(fields[1], "%") {
+		return 0, false
+	}
+
+	bytesDone, err := strconv.ParseInt(strings.ReplaceAll(fields[0], ",", ""), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return bytesDone, true
 }
 
-// doTransferFiles transfers multiple files using rsync (internal implementation)
-func (r *RsyncTransfer) doTransferFiles(files []types.FileTransfer) error {
-	// For small numbers of files, transfer individually
-	if len(files) <= 3 {
+// doTransferFiles transfers multiple files, running one rsync per (source directory, destination directory)
+// group instead of one process and SSH handshake per file
+func (r *RsyncTransfer) doTransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error) {
+	type groupKey struct {
+		sourceDir string
+		destDir   string
+	}
+
+	groups := make(map[groupKey][]types.FileTransfer)
+	var order []groupKey
+	var results []types.FileTransferResult
+
+	for _, file := range files {
+		// --files-from keeps file names, so renamed files go through the single-file path
+		if filepath.Base(file.SourcePath) != filepath.Base(file.DestPath) {
+			transferred, err := r.transferSingle(file.SourcePath, file.DestPath)
+			results = append(results, newFileTransferResult(file, transferred, err))
+			continue
+		}
+
+		key := groupKey{sourceDir: filepath.Dir(file.SourcePath), destDir: filepath.Dir(file.DestPath)}
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], file)
+	}
+
+	for _, key := range order {
+		results = append(results, r.transferGroup(key.sourceDir, key.destDir, groups[key])...)
+	}
+
+	return results, nil
+}
+
+// batchesFiles reports that rsync skips unchanged files itself, so whole batches can be handed over
+func (r *RsyncTransfer) batchesFiles() bool {
+	return true
+}
+
+// transferGroup pushes all files of one directory in a single rsync invocation and derives
+// per-file results from the --itemize-changes output
+func (r *RsyncTransfer) transferGroup(sourceDir, destDir string, files []types.FileTransfer) []types.FileTransferResult {
+	results := make([]types.FileTransferResult, 0, len(files))
+
+	listFile, err := r.createFilesFromList(files)
+	if err != nil {
 		for _, file := range files {
-			if err := r.doTransferFile(file.SourcePath, file.DestPath); err != nil {
-				return err
+			results = append(results, newFileTransferResult(file, false, fmt.Errorf("failed to create file list: %w", err)))
+		}
+		return results
+	}
+	defer os.Remove(listFile)
+
+	source, dest := r.endpoints(sourceDir, destDir)
+	args := r.buildBaseArgs()
+	args = append(args,
+		"--from0", // NUL-separated list, safe for any file name
+		"--files-from="+listFile,
+		source+"/",
+		dest+"/",
+	)
+
+	// Map itemized names back to the group's files so progress can be attributed while rsync runs
+	byName := make(map[string]types.FileTransfer, len(files))
+	for _, file := range files {
+		byName[filepath.Base(file.SourcePath)] = file
+	}
+
+	output, runErr := r.runRsync(args, func(line string) {
+		if bytesDone, ok := parseProgressLine(line); ok {
+			r.progress.UpdateFile(bytesDone)
+			return
+		}
+		if name, ok := parseItemizeLine(line); ok {
+			if file, exists := byName[name]; exists {
+				r.progress.EndFile(true)
+				r.progress.StartFile(file.SourcePath, file.DestPath, file.Size)
 			}
 		}
-		return nil
+	})
+	r.progress.EndFile(runErr == nil)
+	transferred := parseItemizedFiles(output)
+
+	if runErr != nil {
+		r.logger.WithFields(map[string]interface{}{
+			"source_dir": sourceDir,
+			"dest_dir":   destDir,
+			"file_count": len(files),
+			"rsync_args": strings.Join(args, " "),
+			"output":     output,
+		}).Error("Batch rsync failed")
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file.SourcePath)
+		switch {
+		case transferred[name]:
+			results = append(results, newFileTransferResult(file, true, nil))
+		case runErr != nil:
+			results = append(results, newFileTransferResult(file, false, fmt.Errorf("batch rsync failed: %w", runErr)))
+		default:
+			r.progress.SkipFile(file.Size)
+			results = append(results, newFileTransferResult(file, false, nil))
+		}
+	}
+
+	r.logger.WithFields(map[string]interface{}{
+		"source_dir":  sourceDir,
+		"dest_dir":    destDir,
+		"file_count":  len(files),
+		"transferred": len(transferred),
+	}).Debug("Batch rsync completed")
+
+	return results
+}
+
+// createFilesFromList writes the NUL-separated file names of a group for --files-from
+func (r *RsyncTransfer) createFilesFromList(files []types.FileTransfer) (string, error) {
+	tmpFile, err := os.CreateTemp("", "rsync-files-*.lst")
+	if err != nil {
+		return "", err
+	}
+	defer tmpFile.Close()
+
+	for _, file := range files {
+		if _, err := tmpFile.WriteString(filepath.Base(file.SourcePath) + "\x00"); err != nil {
+			os.Remove(tmpFile.Name())
+			return "", err
+		}
+	}
+
+	return tmpFile.Name(), nil
+}
+
+// parseItemizedFiles returns the names of files rsync reported as sent in --itemize-changes output.
+// Unchanged files are not itemized at the default verbosity.
+func parseItemizedFiles(output string) map[string]bool {
+	transferred := make(map[string]bool)
+
+	// --progress rewrites its line with carriage returns, so split on both
+	lines := strings.FieldsFunc(output, func(r rune) bool { return r == '\n' || r == '\r' })
+	for _, line := range lines {
+		if name, ok := parseItemizeLine(line); ok {
+			transferred[name] = true
+		}
+	}
+
+	return transferred
+}
+
+// parseItemizeLine returns the base name of a file from an --itemize-changes line reporting a file transfer
+func parseItemizeLine(line string) (string, bool) {
+	code, name, found := strings.Cut(line, " ")
+	if !found || len(code) < 2 {
+		return "", false
+	}
+	// '<' = sent to the remote host, '>' = received; the second character is the file type
+	if (code[0] != '<' && code[0] != '>') || code[1] != 'f' {
+		return "", false
+	}
+	return filepath.Base(unescapeRsyncName(strings.TrimSpace(name))), true
+}
+
+// unescapeRsyncName decodes rsync's \#ooo octal escapes for unprintable characters in file names
+func unescapeRsyncName(name string) string {
+	if !strings.Contains(name, "\\#") {
+		return name
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+4 < len(name) && name[i+1] == '#' {
+			if value, err := strconv.ParseUint(name[i+2:i+5], 8, 8); err == nil {
+				builder.WriteByte(byte(value))
+				i += 4
+				continue
+			}
+		}
+		builder.WriteByte(name[i])
 	}
+	return builder.String()
+}
 
-	// For larger batches, use rsync's batch capabilities
-	return r.transferFilesBatch(files)
+// newFileTransferResult builds a per-file result from a transfer outcome
+func newFileTransferResult(file types.FileTransfer, transferred bool, err error) types.FileTransferResult {
+	result := types.FileTransferResult{
+		SourcePath: file.SourcePath,
+		DestPath:   file.DestPath,
+		Size:       file.Size,
+		Status:     types.FileTransferSkipped,
+	}
+	if err != nil {
+		result.Status = types.FileTransferFailed
+		result.Error = err.Error()
+	} else if transferred {
+		result.Status = types.FileTransferTransferred
+	}
+	return result
 }
 
 // buildRsyncArgs builds optimized rsync arguments
 func (r *RsyncTransfer) buildRsyncArgs(sourcePath, destPath string) []string {
-	remoteHost := fmt.Sprintf("%s@%s", r.sshConfig.User, r.serverConfig.Host)
-
 	// For exec.Command, we don't need shell quoting - Go handles argument separation
-	remoteDest := fmt.Sprintf("%s:%s", remoteHost, destPath)
+	args := r.buildBaseArgs()
+	source, dest := r.endpoints(sourcePath, destPath)
+	return append(args, source, dest)
+}
 
+// endpoints returns the rsync source and destination arguments: the destination is remote when pushing,
+// the source when pulling
+func (r *RsyncTransfer) endpoints(sourcePath, destPath string) (string, string) {
+	if r.pull {
+		return r.remoteSpec(sourcePath), destPath
+	}
+	return sourcePath, r.remoteSpec(destPath)
+}
+
+// remoteSpec returns the user@host:path form of a path on the SSH host
+func (r *RsyncTransfer) remoteSpec(path string) string {
+	return fmt.Sprintf("%s@%s:%s", r.sshConfig.User, r.sshConfig.Host, path)
+}
+
+// buildBaseArgs builds the rsync options shared by single-file and batch transfers
+func (r *RsyncTransfer) buildBaseArgs() []string {
+	// rsync writes to a hidden temp file in the destination directory and renames it on success,
+	// so the destination Plex server never scans a half-written file
 	args := []string{
 		"-avz",              // Archive mode, verbose, compression
 		"--progress",        // Show progress
-		"--partial",         // Keep partial transfers
-		"--inplace",         // Update files in place (faster for large files)
-		"--itemize-changes", // Show detailed changes (helps detect skips)
+		"--itemize-changes", // Show detailed changes (helps detect skips and per-file results)
 	}
+	args = append(args, r.partialArgs()...)
+	args = append(args, r.bandwidthArgs()...)
 
 	// Compression settings
 	if r.compressionLevel > 0 {
@@ -131,6 +424,11 @@ func (r *RsyncTransfer) buildRsyncArgs(sourcePath, destPath string) []string {
 		sshOpts = append(sshOpts, "-p", r.sshConfig.Port)
 	}
 
+	// Route through the jump host chain; each option is quoted because rsync splits -e on whitespace
+	for _, arg := range jumpHostArgs(r.sshConfig.JumpHosts, r.sshConfig.Host, sshPort(r.sshConfig)) {
+		sshOpts = append(sshOpts, shellQuote(arg))
+	}
+
 	// Build SSH command - use sshpass for password authentication
 	var sshCmd string
 	if r.sshConfig.Password != "" {
@@ -141,10 +439,33 @@ func (r *RsyncTransfer) buildRsyncArgs(sourcePath, destPath string) []string {
 		r.logger.Debug("Using SSH key-based authentication")
 	}
 
-	args = append(args, "-e", sshCmd)
-	args = append(args, sourcePath, remoteDest)
+	return append(args, "-e", sshCmd)
+}
+
+// partialArgs returns the rsync options for interrupted transfers. With resume enabled, partial data is
+// kept in a hidden directory next to the target (never under the final name) and picked up by the next run.
+func (r *RsyncTransfer) partialArgs() []string {
+	if !r.resumeTransfers {
+		return nil
+	}
+	return []string{"--partial-dir=" + partialDirName}
+}
 
-	return args
+// setBandwidthLimit sets the cap applied to subsequent rsync invocations
+func (r *RsyncTransfer) setBandwidthLimit(bytesPerSecond int64) {
+	r.bandwidthLimit = bytesPerSecond
+}
+
+// bandwidthArgs returns the rsync --bwlimit option (rsync expects KiB per second)
+func (r *RsyncTransfer) bandwidthArgs() []string {
+	if r.bandwidthLimit <= 0 {
+		return nil
+	}
+	kibPerSecond := r.bandwidthLimit / 1024
+	if kibPerSecond < 1 {
+		kibPerSecond = 1
+	}
+	return []string{fmt.Sprintf("--bwlimit=%d", kibPerSecond)}
 }
 
 // isFileSkipped analyzes rsync output to determine if the file was skipped (not transferred)
@@ -153,7 +474,7 @@ func (r *RsyncTransfer) isFileSkipped(output, sourcePath string) bool {
 	filename := filepath.Base(sourcePath)
 
 	// Look for itemize-changes output: lines starting with itemize codes
-	// If file was transferred, we'd see something like ">f+++++++++" (new file) or ">f.st......" (updated file)
+	// If file was transferred, we'd see something like "<f+++++++++" (new file) or "<f.st......" (updated file)
 	// If file was skipped, there will be no itemize line for this file, or minimal output
 
 	hasItemizeOutput := false
@@ -163,7 +484,7 @@ func (r *RsyncTransfer) isFileSkipped(output, sourcePath string) bool {
 		line = strings.TrimSpace(line)
 
 		// Check for itemize-changes output (indicates actual changes)
-		if strings.HasPrefix(line, ">f") && strings.Contains(line, filename) {
+		if (strings.HasPrefix(line, "<f") || strings.HasPrefix(line, ">f")) && strings.Contains(line, filename) {
 			hasItemizeOutput = true
 			r.logger.WithFields(map[string]interface{}{
 				"itemize_line": line,
@@ -196,122 +517,7 @@ func (r *RsyncTransfer) isFileSkipped(output, sourcePath string) bool {
 	return fileSkipped
 }
 
-// transferFilesBatch transfers multiple files in batches for efficiency
-func (r *RsyncTransfer) transferFilesBatch(files []types.FileTransfer) error {
-	// Group files by directory for more efficient transfers
-	dirGroups := make(map[string][]types.FileTransfer)
-
-	for _, file := range files {
-		sourceDir := filepath.Dir(file.SourcePath)
-		dirGroups[sourceDir] = append(dirGroups[sourceDir], file)
-	}
-
-	// Transfer each directory group
-	for sourceDir, dirFiles := range dirGroups {
-		if err := r.transferDirectoryBatch(sourceDir, dirFiles); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// transferDirectoryBatch transfers all files in a directory efficiently
-func (r *RsyncTransfer) transferDirectoryBatch(sourceDir string, files []types.FileTransfer) error {
-	if len(files) == 0 {
-		return nil
-	}
-
-	// Create include file for specific files
-	includeFile, err := r.createIncludeFile(sourceDir, files)
-	if err != nil {
-		return fmt.Errorf("failed to create include file: %w", err)
-	}
-	defer os.Remove(includeFile)
-
-	// Use first file's destination to determine target directory
-	destDir := filepath.Dir(files[0].DestPath)
-
-	// Directory creation is now handled by the common transferrer before calling transfer methods
-
-	remoteHost := fmt.Sprintf("%s@%s", r.sshConfig.User, r.serverConfig.Host)
-	remoteDest := fmt.Sprintf("%s:%s/", remoteHost, destDir)
-
-	args := []string{
-		"-avz",
-		"--progress",
-		"--partial",
-		"--inplace",
-		fmt.Sprintf("--include-from=%s", includeFile),
-		"--exclude=*", // Exclude everything not in include file
-	}
-
-	// Add SSH options
-	sshOpts := []string{
-		"-o", "Compression=no",
-		"-o", "TCPKeepAlive=yes",
-		"-o", "StrictHostKeyChecking=no",
-	}
-
-	if r.sshConfig.Port != "" && r.sshConfig.Port != "22" {
-		sshOpts = append(sshOpts, "-p", r.sshConfig.Port)
-	}
-
-	// Build SSH command - use sshpass for password authentication
-	var sshCmd string
-	if r.sshConfig.Password != "" {
-		sshCmd = fmt.Sprintf("sshpass -p '%s' ssh %s", r.sshConfig.Password, strings.Join(sshOpts, " "))
-		r.logger.Debug("Using sshpass for batch transfer with SSH password authentication")
-	} else {
-		sshCmd = fmt.Sprintf("ssh %s", strings.Join(sshOpts, " "))
-		r.logger.Debug("Using SSH key-based authentication for batch transfer")
-	}
-
-	args = append(args, "-e", sshCmd)
-	args = append(args, sourceDir+"/", remoteDest)
-
-	cmd := exec.Command("rsync", args...)
-	output, err := cmd.CombinedOutput()
-
-	if err != nil {
-		r.logger.WithFields(map[string]interface{}{
-			"source_dir": sourceDir,
-			"dest_dir":   destDir,
-			"file_count": len(files),
-			"rsync_args": strings.Join(args, " "),
-			"output":     string(output),
-		}).Error("Batch rsync failed")
-		return fmt.Errorf("batch rsync failed: %w", err)
-	}
-
-	return nil
-}
-
-// createIncludeFile creates a temporary file listing specific files to include
-func (r *RsyncTransfer) createIncludeFile(baseDir string, files []types.FileTransfer) (string, error) {
-	tmpFile, err := os.CreateTemp("", "rsync-include-*.txt")
-	if err != nil {
-		return "", err
-	}
-	defer tmpFile.Close()
-
-	for _, file := range files {
-		// Get relative path from base directory
-		relPath, err := filepath.Rel(baseDir, file.SourcePath)
-		if err != nil {
-			return "", fmt.Errorf("failed to get relative path: %w", err)
-		}
-
-		// Write to include file
-		if _, err := fmt.Fprintln(tmpFile, relPath); err != nil {
-			return "", err
-		}
-	}
-
-	return tmpFile.Name(), nil
-}
-
 // TransferFiles transfers multiple files using rsync (public interface for backward compatibility)
-func (r *RsyncTransfer) TransferFiles(files []types.FileTransfer) error {
+func (r *RsyncTransfer) TransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error) {
 	return r.doTransferFiles(files)
 }
@@ -3,41 +3,55 @@ package transfer
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nullable-eth/syncarr/internal/config"
 	"github.com/nullable-eth/syncarr/internal/logger"
 	"github.com/nullable-eth/syncarr/pkg/types"
 )
 
+// scpProgressPollInterval is how often the remote temp file is measured during an scp transfer
+const scpProgressPollInterval = 5 * time.Second
+
 // SCPTransfer handles file transfers using actual SCP commands over SSH
 type SCPTransfer struct {
 	sshConfig         *config.SSHConfig
-	serverConfig      *config.PlexServerConfig
 	sourceReplaceFrom string
 	sourceReplaceTo   string
 	destRootDir       string
+	fileOps           fileOperations // Destination file system, used to move completed temp files into place
+	sourceOps         fileOperations
+	pull              bool  // Fetch from the source host into local paths instead of pushing to the destination
+	bandwidthLimit    int64 // Bytes per second, 0 = unlimited
+	progress          *ProgressTracker
 	logger            *logger.Logger
 }
 
 // newSCPTransfer creates a new SCP transfer instance (package-private)
-func newSCPTransfer(cfg *config.Config, log *logger.Logger) (*SCPTransfer, error) {
+func newSCPTransfer(cfg *config.Config, sourceOps, fileOps fileOperations, progress *ProgressTracker, log *logger.Logger) (*SCPTransfer, error) {
 	return &SCPTransfer{
+		sourceOps:         sourceOps,
+		pull:              cfg.TransferDirection == TransferDirectionPull,
+		progress:          progress,
 		sshConfig:         &cfg.SSH,
-		serverConfig:      &cfg.Destination,
 		sourceReplaceFrom: cfg.SourceReplaceFrom,
 		sourceReplaceTo:   cfg.SourceReplaceTo,
 		destRootDir:       cfg.DestRootDir,
+		fileOps:           fileOps,
 		logger:            log,
 	}, nil
 }
 
-// doTransferFile transfers a single file using actual SCP command
+// doTransferFile transfers a single file using actual SCP command. The file is written to a hidden
+// temp name in the destination directory and renamed on success so Plex never sees a partial file.
 func (s *SCPTransfer) doTransferFile(sourcePath, destPath string) error {
 	// Directory creation is now handled by the common transferrer before calling this method
+	tempPath := tempPathFor(destPath)
 
 	// Build SCP command
-	args := s.buildSCPArgs(sourcePath, destPath)
+	args := s.buildSCPArgs(sourcePath, tempPath)
 
 	var cmd *exec.Cmd
 	if s.sshConfig.Password != "" {
@@ -51,8 +65,25 @@ func (s *SCPTransfer) doTransferFile(sourcePath, destPath string) error {
 		cmd = exec.Command("scp", args...)
 	}
 
+	var size int64
+	if info, statErr := s.sourceOps.StatFile(sourcePath); statErr == nil {
+		size = info.Size
+	}
+	s.progress.StartFile(sourcePath, destPath, size)
+
+	// scp prints no parseable progress without a TTY, so poll the size of the remote temp file instead
+	stopPolling := make(chan struct{})
+	pollingDone := make(chan struct{})
+	go func() {
+		defer close(pollingDone)
+		s.pollRemoteProgress(tempPath, stopPolling)
+	}()
+
 	// Capture output for debugging
 	output, err := cmd.CombinedOutput()
+	close(stopPolling)
+	<-pollingDone
+	s.progress.EndFile(err == nil)
 	if err != nil {
 		s.logger.WithFields(map[string]interface{}{
 			"source_path": sourcePath,
@@ -60,26 +91,56 @@ func (s *SCPTransfer) doTransferFile(sourcePath, destPath string) error {
 			"scp_args":    strings.Join(args, " "),
 			"output":      string(output),
 		}).Error("SCP command failed")
+		// scp cannot resume, so never leave the partial temp file behind
+		if removeErr := s.fileOps.DeleteFile(tempPath); removeErr != nil {
+			s.logger.WithError(removeErr).WithField("temp_path", tempPath).Debug("Failed to remove partial temp file")
+		}
 		return fmt.Errorf("scp failed: %w", err)
 	}
 
+	if err := s.fileOps.RenameFile(tempPath, destPath); err != nil {
+		return fmt.Errorf("failed to move temp file into place: %w", err)
+	}
+
 	return nil
 }
 
+// pollRemoteProgress periodically reports the size of the file being written until stop is closed
+func (s *SCPTransfer) pollRemoteProgress(tempPath string, stop <-chan struct{}) {
+	ticker := time.NewTicker(scpProgressPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if size, err := s.fileOps.GetFileSize(tempPath); err == nil {
+				s.progress.UpdateFile(size)
+			}
+		}
+	}
+}
+
 // Note: escapeShellPath removed - not needed for exec.Command as Go handles argument separation
 
 // buildSCPArgs builds the SCP command arguments
 func (s *SCPTransfer) buildSCPArgs(sourcePath, destPath string) []string {
-	remoteHost := fmt.Sprintf("%s@%s", s.sshConfig.User, s.serverConfig.Host)
+	remoteHost := fmt.Sprintf("%s@%s", s.sshConfig.User, s.sshConfig.Host)
 
 	// For exec.Command, we don't need shell quoting - Go handles argument separation
-	// The remote destination still needs proper formatting for the remote shell
-	remoteDest := fmt.Sprintf("%s:%s", remoteHost, destPath)
+	// The remote side still needs proper formatting for the remote shell; it is the source when pulling
+	source, dest := sourcePath, fmt.Sprintf("%s:%s", remoteHost, destPath)
+	if s.pull {
+		source, dest = fmt.Sprintf("%s:%s", remoteHost, sourcePath), destPath
+	}
 
 	args := []string{
 		"-o", "StrictHostKeyChecking=no",
 		"-o", "UserKnownHostsFile=/dev/null",
 		"-o", "ConnectTimeout=30",
+		"-o", "ServerAliveInterval=30", // Abort instead of hanging when the link drops mid-transfer
+		"-o", "ServerAliveCountMax=6",
 		"-C", // Enable compression
 	}
 
@@ -88,19 +149,41 @@ func (s *SCPTransfer) buildSCPArgs(sourcePath, destPath string) []string {
 		args = append(args, "-P", s.sshConfig.Port)
 	}
 
-	// Add source path and remote destination - no quotes needed for exec.Command
-	args = append(args, sourcePath, remoteDest)
+	// Route through the jump host chain
+	args = append(args, jumpHostArgs(s.sshConfig.JumpHosts, s.sshConfig.Host, sshPort(s.sshConfig))...)
+
+	// Bandwidth limit (scp expects Kbit per second)
+	if s.bandwidthLimit > 0 {
+		kbitPerSecond := s.bandwidthLimit * 8 / 1000
+		if kbitPerSecond < 1 {
+			kbitPerSecond = 1
+		}
+		args = append(args, "-l", strconv.FormatInt(kbitPerSecond, 10))
+	}
+
+	// Add source and destination - no quotes needed for exec.Command
+	args = append(args, source, dest)
 	return args
 }
 
+// setBandwidthLimit sets the cap applied to subsequent scp invocations
+func (s *SCPTransfer) setBandwidthLimit(bytesPerSecond int64) {
+	s.bandwidthLimit = bytesPerSecond
+}
+
 // doTransferFiles transfers multiple files using SCP
-func (s *SCPTransfer) doTransferFiles(files []types.FileTransfer) error {
+func (s *SCPTransfer) doTransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error) {
 	// SCP can handle multiple files in one command, but for simplicity and error handling,
 	// we'll transfer them individually
+	results := make([]types.FileTransferResult, 0, len(files))
 	for _, file := range files {
-		if err := s.doTransferFile(file.SourcePath, file.DestPath); err != nil {
-			return err
-		}
+		err := s.doTransferFile(file.SourcePath, file.DestPath)
+		results = append(results, newFileTransferResult(file, err == nil, err))
 	}
-	return nil
+	return results, nil
+}
+
+// batchesFiles reports that scp cannot detect unchanged files itself, so the common size check must run first
+func (s *SCPTransfer) batchesFiles() bool {
+	return false
 }
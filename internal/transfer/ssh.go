@@ -2,81 +2,59 @@
 package transfer
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/nullable-eth/syncarr/internal/config"
 	"github.com/nullable-eth/syncarr/internal/logger"
-	"golang.org/x/crypto/ssh"
 )
 
-// fileOperations defines the interface for SSH-based file operations
+// fileOperations defines the interface for destination file operations (SFTP or a local mount)
 type fileOperations interface {
 	GetFileSize(path string) (int64, error)
 	DeleteFile(path string) error
 	ListDirectoryContents(rootPath string) ([]string, error)
+	ListFiles(dir string) ([]string, error)
 	CreateDirectory(path string) error
+	RenameFile(oldPath, newPath string) error
+	ListFileSizes(rootPath string) (map[string]int64, error)
+	GetFreeSpace(path string) (int64, error)
+	StatFile(path string) (*remoteFileInfo, error)
+	ComputeChecksum(path string, algorithm checksumAlgorithm) (string, error)
+	LinkFile(sourcePath, destPath string, mode linkMode) error
 	Close() error
 }
 
-// sshClient handles all SSH-based file operations with persistent connection
-type sshClient struct {
-	sshConfig    *config.SSHConfig
-	serverConfig *config.PlexServerConfig
-	logger       *logger.Logger
-	client       *ssh.Client // Persistent SSH connection (reused for multiple sessions)
+// remoteFileInfo holds the attributes of a remote file needed for change detection
+type remoteFileInfo struct {
+	Size    int64
+	ModTime int64 // Unix seconds
 }
 
-// getSSHClient creates and returns an SSH client connection
-func (s *sshClient) getSSHClient() (*ssh.Client, error) {
-	if s.client != nil {
-		return s.client, nil
-	}
-
-	// Create SSH client config
-	config := &ssh.ClientConfig{
-		User:            s.sshConfig.User,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // For simplicity, ignore host key verification
-		Timeout:         30 * time.Second,
-	}
-
-	// Add authentication method
-	if s.sshConfig.Password != "" {
-		config.Auth = []ssh.AuthMethod{
-			ssh.Password(s.sshConfig.Password),
-		}
-	}
-
-	// Determine port
-	port := s.sshConfig.Port
-	if port == "" {
-		port = "22"
-	}
-
-	// Connect to SSH server
-	addr := fmt.Sprintf("%s:%s", s.serverConfig.Host, port)
-	client, err := ssh.Dial("tcp", addr, config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to SSH server: %w", err)
-	}
-
-	s.client = client
-	return client, nil
+// sshClient handles all SSH-based file operations over a pool of persistent connections
+type sshClient struct {
+	sshConfig *config.SSHConfig
+	logger    *logger.Logger
+	pool      *sshPool // Persistent SSH connections (reused for multiple sessions), redialed when they drop
 }
 
-// executeCommand executes a command using the persistent SSH connection (creates fresh session each time)
+// executeCommand executes a command on a pooled SSH connection (creates fresh session each time)
 func (s *sshClient) executeCommand(cmd string) ([]byte, error) {
-	client, err := s.getSSHClient()
+	conn, err := s.pool.get()
 	if err != nil {
 		return nil, err
 	}
 
 	// Create a fresh session for this command (SSH protocol requirement)
-	session, err := client.NewSession()
+	session, err := conn.client.NewSession()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create SSH session: %w", err)
+		s.pool.invalidate(conn)
+		return nil, fmt.Errorf("%w: failed to create SSH session: %w", ErrConnection, err)
 	}
 	defer session.Close()
 
@@ -93,114 +71,276 @@ func (s *sshClient) executeCommand(cmd string) ([]byte, error) {
 	return output, nil
 }
 
-// GetFileSize returns the size of a remote file using persistent connection
+// GetFileSize returns the size of a remote file
 func (s *sshClient) GetFileSize(path string) (int64, error) {
-	// Properly escape the path for shell execution
-	escapedPath := strings.ReplaceAll(path, "'", "'\"'\"'")
-	cmd := fmt.Sprintf("stat -f%%z '%s' 2>/dev/null || stat -c%%s '%s'", escapedPath, escapedPath)
-
-	output, err := s.executeCommand(cmd)
+	info, err := s.stat(path)
 	if err != nil {
 		return 0, err
 	}
+	return info.Size(), nil
+}
 
-	size, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+// DeleteFile deletes a remote file (a missing file is not an error, matching rm -f)
+func (s *sshClient) DeleteFile(path string) error {
+	conn, err := s.pool.get()
 	if err != nil {
-		return 0, err
+		return newRemoteError("remove", path, err)
 	}
 
-	return size, nil
+	if err := conn.sftp.Remove(path); err != nil {
+		remoteErr := s.remoteError(conn, "remove", path, err)
+		if errors.Is(remoteErr, ErrRemoteNotFound) {
+			return nil
+		}
+		return remoteErr
+	}
+	return nil
 }
 
-// DeleteFile deletes a file on the remote server using persistent connection
-func (s *sshClient) DeleteFile(path string) error {
-	// Properly escape the path for shell execution
-	escapedPath := strings.ReplaceAll(path, "'", "'\"'\"'")
-	cmd := fmt.Sprintf("rm -f '%s'", escapedPath)
+// ListDirectoryContents recursively lists all files below rootPath. A missing root yields an empty list;
+// any other failure is returned so cleanup never mistakes it for an empty destination.
+func (s *sshClient) ListDirectoryContents(rootPath string) ([]string, error) {
+	sizes, err := s.ListFileSizes(rootPath)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := s.executeCommand(cmd)
-	return err
+	files := make([]string, 0, len(sizes))
+	for path := range sizes {
+		files = append(files, path)
+	}
+	return files, nil
 }
 
-// ListDirectoryContents recursively lists all files in a directory using persistent connection
-func (s *sshClient) ListDirectoryContents(rootPath string) ([]string, error) {
-	// Properly escape the path for shell execution
-	escapedRootPath := strings.ReplaceAll(rootPath, "'", "'\"'\"'")
+// ListFiles returns the names of the regular files directly inside a remote directory.
+// Symlinks are followed, so sidecars linked into the directory are listed too; broken links are left out.
+func (s *sshClient) ListFiles(dir string) ([]string, error) {
+	conn, err := s.pool.get()
+	if err != nil {
+		return nil, newRemoteError("readdir", dir, err)
+	}
 
-	// Try find first (preferred for recursive file listing)
-	findCmd := fmt.Sprintf("find '%s' -type f 2>/dev/null", escapedRootPath)
+	entries, err := conn.sftp.ReadDir(dir)
+	if err != nil {
+		return nil, s.remoteError(conn, "readdir", dir, err)
+	}
 
-	s.logger.WithFields(map[string]interface{}{
-		"root_path": rootPath,
-		"command":   findCmd,
-	}).Debug("Executing directory listing with find")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Mode()&fs.ModeSymlink != 0 {
+			if info, err := conn.sftp.Stat(conn.sftp.Join(dir, entry.Name())); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+		} else if !entry.Mode().IsRegular() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
 
-	output, err := s.executeCommand(findCmd)
+// CreateDirectory creates a remote directory and its parents
+func (s *sshClient) CreateDirectory(path string) error {
+	conn, err := s.pool.get()
 	if err != nil {
-		s.logger.WithFields(map[string]interface{}{
-			"root_path":  rootPath,
-			"find_error": err.Error(),
-		}).Debug("Find command failed, falling back to ls")
+		return newRemoteError("mkdir", path, err)
+	}
 
-		// Fallback: try a basic directory test and simpler find
-		testCmd := fmt.Sprintf("test -d '%s' && find '%s' -type f || echo \"\"", escapedRootPath, escapedRootPath)
+	if err := conn.sftp.MkdirAll(path); err != nil {
+		return s.remoteError(conn, "mkdir", path, err)
+	}
 
-		s.logger.WithFields(map[string]interface{}{
-			"root_path": rootPath,
-			"command":   testCmd,
-		}).Debug("Executing directory listing with simpler fallback")
-
-		output, err = s.executeCommand(testCmd)
-		if err != nil {
-			s.logger.WithFields(map[string]interface{}{
-				"root_path": rootPath,
-				"error":     err.Error(),
-			}).Warn("Directory listing failed completely, assuming empty directory")
-			// Return empty list instead of failing - cleanup can continue
-			return []string{}, nil
+	s.logger.WithField("dest_dir", path).Debug("Remote directory created successfully")
+	return nil
+}
+
+// RenameFile moves a remote file into place, replacing any existing file at newPath
+func (s *sshClient) RenameFile(oldPath, newPath string) error {
+	conn, err := s.pool.get()
+	if err != nil {
+		return newRemoteError("rename", oldPath, err)
+	}
+
+	// POSIX rename replaces the target atomically; plain SFTP rename refuses to overwrite
+	if _, ok := conn.sftp.HasExtension("posix-rename@openssh.com"); ok {
+		err = conn.sftp.PosixRename(oldPath, newPath)
+	} else {
+		if removeErr := conn.sftp.Remove(newPath); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+			return s.remoteError(conn, "rename", newPath, removeErr)
 		}
+		err = conn.sftp.Rename(oldPath, newPath)
+	}
+	if err != nil {
+		return s.remoteError(conn, "rename", oldPath, err)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"old_path": oldPath,
+		"new_path": newPath,
+	}).Debug("Remote file renamed successfully")
+	return nil
+}
+
+// ListFileSizes recursively lists all files below rootPath with their sizes. A missing root yields an empty map;
+// any other failure is returned so callers never mistake it for an empty destination.
+func (s *sshClient) ListFileSizes(rootPath string) (map[string]int64, error) {
+	conn, err := s.pool.get()
+	if err != nil {
+		return nil, newRemoteError("list", rootPath, err)
 	}
 
-	files := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(files) == 1 && files[0] == "" {
-		return []string{}, nil
+	sizes := make(map[string]int64)
+	walker := conn.sftp.Walk(rootPath)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			remoteErr := s.remoteError(conn, "list", walker.Path(), err)
+			if walker.Path() == rootPath && errors.Is(remoteErr, ErrRemoteNotFound) {
+				return sizes, nil
+			}
+			return nil, fmt.Errorf("failed to list files under %s: %w", rootPath, remoteErr)
+		}
+		if info := walker.Stat(); info.Mode().IsRegular() {
+			sizes[walker.Path()] = info.Size()
+		}
 	}
 
 	s.logger.WithFields(map[string]interface{}{
 		"root_path":  rootPath,
-		"file_count": len(files),
-	}).Debug("Listed directory contents via SSH")
+		"file_count": len(sizes),
+	}).Debug("Listed destination files with sizes via SFTP")
 
-	return files, nil
+	return sizes, nil
 }
 
-// CreateDirectory creates a directory on the remote server using persistent connection
-func (s *sshClient) CreateDirectory(path string) error {
-	// Properly escape the path for shell execution
-	escapedPath := strings.ReplaceAll(path, "'", "'\"'\"'")
-	cmd := fmt.Sprintf("mkdir -p '%s'", escapedPath)
+// GetFreeSpace returns the bytes available to unprivileged users on the filesystem holding path.
+// If path does not exist yet, the nearest existing parent directory is measured. Servers without the
+// statvfs SFTP extension are measured with df instead.
+func (s *sshClient) GetFreeSpace(path string) (int64, error) {
+	conn, err := s.pool.get()
+	if err != nil {
+		return 0, newRemoteError("statvfs", path, err)
+	}
+	_, hasStatVFS := conn.sftp.HasExtension("statvfs@openssh.com")
+
+	var lastErr error
+	for current := path; ; current = filepath.Dir(current) {
+		if hasStatVFS {
+			stat, err := conn.sftp.StatVFS(current)
+			if err == nil {
+				return int64(stat.Bavail * stat.Frsize), nil
+			}
+			lastErr = s.remoteError(conn, "statvfs", current, err)
+			if errors.Is(lastErr, ErrConnection) {
+				break
+			}
+		} else {
+			output, err := s.executeCommand(fmt.Sprintf("df -Pk %s", shellQuote(current)))
+			if err == nil {
+				return parseDfAvailable(string(output))
+			}
+			lastErr = err
+		}
 
-	_, err := s.executeCommand(cmd)
+		if parent := filepath.Dir(current); parent == current {
+			break
+		}
+	}
+	return 0, fmt.Errorf("failed to determine free space for %s: %w", path, lastErr)
+}
+
+// parseDfAvailable extracts the available bytes from POSIX "df -Pk" output
+func parseDfAvailable(output string) (int64, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, fmt.Errorf("unexpected df output: %q", output)
+	}
+
+	// Filesystem 1024-blocks Used Available Capacity Mounted-on
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("unexpected df output: %q", output)
+	}
+
+	availableKB, err := strconv.ParseInt(fields[3], 10, 64)
 	if err != nil {
-		s.logger.WithFields(map[string]interface{}{
-			"dest_dir": path,
-			"error":    err.Error(),
-		}).Warn("Failed to create remote directory (may already exist)")
-		// Don't return error - directory might already exist
-	} else {
-		s.logger.WithField("dest_dir", path).Debug("Remote directory created successfully")
+		return 0, fmt.Errorf("failed to parse available space: %w", err)
 	}
+	return availableKB * 1024, nil
+}
 
-	return nil
+// StatFile returns the size and modification time of a remote file
+func (s *sshClient) StatFile(path string) (*remoteFileInfo, error) {
+	info, err := s.stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteFileInfo{Size: info.Size(), ModTime: info.ModTime().Unix()}, nil
 }
 
-// Close closes the SSH connection
-func (s *sshClient) Close() error {
-	if s.client != nil {
-		err := s.client.Close()
-		s.client = nil
-		s.logger.Debug("SSH client connection closed successfully")
-		return err
+// stat returns the SFTP attributes of a remote path
+func (s *sshClient) stat(path string) (os.FileInfo, error) {
+	conn, err := s.pool.get()
+	if err != nil {
+		return nil, newRemoteError("stat", path, err)
+	}
+
+	info, err := conn.sftp.Stat(path)
+	if err != nil {
+		return nil, s.remoteError(conn, "stat", path, err)
+	}
+	return info, nil
+}
+
+// ComputeChecksum hashes a remote file with the first available hashing tool on the remote host
+func (s *sshClient) ComputeChecksum(path string, algorithm checksumAlgorithm) (string, error) {
+	commands := algorithm.remoteCommands()
+	if len(commands) == 0 {
+		return "", fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+
+	quotedPath := shellQuote(path)
+	parts := make([]string, len(commands))
+	for i, command := range commands {
+		parts[i] = fmt.Sprintf("%s %s 2>/dev/null", command, quotedPath)
+	}
+	cmd := strings.Join(parts, " || ")
+
+	output, err := s.executeCommand(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute remote checksum: %w", err)
+	}
+
+	// All supported tools print the hash as the first field
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum output for %s", path)
 	}
+
+	return strings.ToLower(fields[0]), nil
+}
+
+// shellQuote wraps a value in single quotes for safe use in a remote shell command
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "'\"'\"'") + "'"
+}
+
+// remoteError classifies a failed SFTP operation and drops the connection when it is no longer usable,
+// so the next operation redials it
+func (s *sshClient) remoteError(conn *sshConnection, op, path string, err error) error {
+	remoteErr := newRemoteError(op, path, err)
+	if errors.Is(remoteErr, ErrConnection) {
+		s.logger.WithError(err).WithFields(map[string]interface{}{
+			"operation": op,
+			"path":      path,
+		}).Debug("Destination operation failed on a broken connection")
+		s.pool.invalidate(conn)
+	}
+	return remoteErr
+}
+
+// Close closes all pooled SSH connections
+func (s *sshClient) Close() error {
+	s.pool.close()
+	s.logger.Debug("SSH client connections closed successfully")
 	return nil
 }
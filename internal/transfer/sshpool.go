@@ -0,0 +1,264 @@
+package transfer
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nullable-eth/syncarr/internal/config"
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// keepaliveMaxMissed is how many unanswered keepalive requests mark a connection as dead
+	keepaliveMaxMissed = 3
+	// maxReconnectBackoff caps the wait between redial attempts
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// sshConnection is one pooled SSH connection with its SFTP session
+type sshConnection struct {
+	id     int
+	client *ssh.Client
+	jumps  []*ssh.Client // Jump host connections the client is tunnelled through, in dial order
+	sftp   *sftp.Client
+	closed chan struct{}
+	once   sync.Once
+}
+
+// isAlive reports whether the connection has not been closed or detected as dead
+func (c *sshConnection) isAlive() bool {
+	select {
+	case <-c.closed:
+		return false
+	default:
+		return true
+	}
+}
+
+// close shuts down the SFTP session and the SSH connection (safe to call more than once)
+func (c *sshConnection) close() {
+	c.once.Do(func() {
+		close(c.closed)
+		c.sftp.Close()
+		c.closeTransport()
+	})
+}
+
+// closeTransport closes the SSH connection and then the jump hosts it runs through
+func (c *sshConnection) closeTransport() {
+	c.client.Close()
+	for i := len(c.jumps) - 1; i >= 0; i-- {
+		c.jumps[i].Close()
+	}
+}
+
+// sshPoolSlot holds the connection of one pool position; the mutex serialises redials of that slot
+type sshPoolSlot struct {
+	mu   sync.Mutex
+	conn *sshConnection
+}
+
+// sshPool keeps a small set of SSH connections to the destination. Callers are spread round-robin over the
+// connections so concurrent transfers don't serialise on one channel; dead connections are redialed on demand.
+type sshPool struct {
+	sshConfig *config.SSHConfig
+	logger    *logger.Logger
+
+	slots []*sshPoolSlot
+	next  atomic.Uint64
+}
+
+// newSSHPool creates a connection pool; connections are dialed lazily on first use
+func newSSHPool(sshConfig *config.SSHConfig, log *logger.Logger) *sshPool {
+	size := sshConfig.PoolSize
+	if size < 1 {
+		size = 1
+	}
+
+	pool := &sshPool{
+		sshConfig: sshConfig,
+		logger:    log,
+		slots:     make([]*sshPoolSlot, size),
+	}
+	for i := range pool.slots {
+		pool.slots[i] = &sshPoolSlot{}
+	}
+	return pool
+}
+
+// get returns a live connection, redialing the chosen slot with backoff if its connection is gone
+func (p *sshPool) get() (*sshConnection, error) {
+	id := int(p.next.Add(1) % uint64(len(p.slots)))
+	slot := p.slots[id]
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	if slot.conn != nil && slot.conn.isAlive() {
+		return slot.conn, nil
+	}
+
+	reconnecting := slot.conn != nil
+	conn, err := p.dialWithBackoff(id)
+	if err != nil {
+		return nil, err
+	}
+	slot.conn = conn
+
+	if reconnecting {
+		p.logger.WithFields(map[string]interface{}{
+			"connection": id,
+			"host":       p.sshConfig.Host,
+		}).Info("Reconnected to destination SSH server")
+	}
+	return conn, nil
+}
+
+// invalidate drops a connection that failed, so the next get redials it
+func (p *sshPool) invalidate(conn *sshConnection) {
+	if conn.isAlive() {
+		p.logger.WithField("connection", conn.id).Warn("Lost connection to destination, reconnecting on next operation")
+	}
+	conn.close()
+}
+
+// close shuts down every pooled connection
+func (p *sshPool) close() {
+	for _, slot := range p.slots {
+		slot.mu.Lock()
+		if slot.conn != nil {
+			slot.conn.close()
+			slot.conn = nil
+		}
+		slot.mu.Unlock()
+	}
+}
+
+// dialWithBackoff dials a connection, retrying with exponential backoff up to SSH_RECONNECT_ATTEMPTS times
+func (p *sshPool) dialWithBackoff(id int) (*sshConnection, error) {
+	attempts := p.sshConfig.ReconnectAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	backoff := time.Second
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		conn, err := p.dial(id)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+
+		if attempt == attempts {
+			break
+		}
+		p.logger.LogRetryAttempt("ssh_connect", attempt, attempts, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxReconnectBackoff)
+	}
+
+	return nil, lastErr
+}
+
+// dial opens an SSH connection with its SFTP session and starts health monitoring
+func (p *sshPool) dial(id int) (*sshConnection, error) {
+	clientConfig, err := newClientConfig(p.sshConfig.User, p.sshConfig.Password, p.sshConfig.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	port := sshPort(p.sshConfig)
+
+	// Connect to SSH server, through the jump host chain when one is configured
+	addr := net.JoinHostPort(p.sshConfig.Host, port)
+	client, jumps, err := dialChain(p.sshConfig.JumpHosts, addr, clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to connect to SSH server: %w", ErrConnection, err)
+	}
+
+	conn := &sshConnection{
+		id:     id,
+		client: client,
+		jumps:  jumps,
+		closed: make(chan struct{}),
+	}
+
+	sftpClient, err := sftp.NewClient(client)
+	if err != nil {
+		conn.closeTransport()
+		return nil, fmt.Errorf("%w: failed to start SFTP subsystem: %w", ErrConnection, err)
+	}
+	conn.sftp = sftpClient
+
+	// The connection is dead as soon as the transport ends, whatever the reason
+	go func() {
+		client.Wait()
+		conn.close()
+	}()
+	if p.sshConfig.KeepaliveInterval > 0 {
+		go p.keepalive(conn)
+	}
+
+	p.logger.WithFields(map[string]interface{}{
+		"connection": id,
+		"host":       p.sshConfig.Host,
+		"port":       port,
+		"jump_hosts": len(jumps),
+	}).Debug("SSH connection established with SFTP session")
+
+	return conn, nil
+}
+
+// keepalive sends periodic keepalive requests and closes the connection once too many go unanswered.
+// Silent network drops (NAT timeouts, sleeping NAS) are otherwise only noticed when the next command hangs.
+func (p *sshPool) keepalive(conn *sshConnection) {
+	ticker := time.NewTicker(p.sshConfig.KeepaliveInterval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-conn.closed:
+			return
+		case <-ticker.C:
+		}
+
+		reply := make(chan error, 1)
+		go func() {
+			_, _, err := conn.client.SendRequest("keepalive@openssh.com", true, nil)
+			reply <- err
+		}()
+
+		var err error
+		select {
+		case err = <-reply:
+		case <-time.After(p.sshConfig.KeepaliveInterval):
+			err = fmt.Errorf("no reply within %s", p.sshConfig.KeepaliveInterval)
+		case <-conn.closed:
+			return
+		}
+
+		if err == nil {
+			missed = 0
+			continue
+		}
+
+		missed++
+		p.logger.WithError(err).WithFields(map[string]interface{}{
+			"connection": conn.id,
+			"missed":     missed,
+		}).Debug("SSH keepalive failed")
+
+		if missed >= keepaliveMaxMissed {
+			p.logger.WithField("connection", conn.id).Warn("SSH connection stopped responding to keepalives, closing it")
+			conn.close()
+			return
+		}
+	}
+}
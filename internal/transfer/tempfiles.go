@@ -0,0 +1,54 @@
+package transfer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// tempFileSuffix marks in-flight destination files that are renamed into place on success
+	tempFileSuffix = ".syncarr-tmp"
+	// resumeInfoSuffix marks the file recording which source version a resumable temp file was copied from
+	resumeInfoSuffix = ".syncarr-resume"
+	// partialDirName is the hidden per-directory folder where rsync keeps interrupted transfers for resuming
+	partialDirName = ".syncarr-partial"
+)
+
+// tempPathFor returns the hidden temporary path used while writing destPath.
+// The temp file lives in the same directory so the final rename is atomic.
+func tempPathFor(destPath string) string {
+	return filepath.Join(filepath.Dir(destPath), "."+filepath.Base(destPath)+tempFileSuffix)
+}
+
+// resumeInfoPathFor returns the hidden path recording the source size and modification time of destPath's temp file
+func resumeInfoPathFor(destPath string) string {
+	return filepath.Join(filepath.Dir(destPath), "."+filepath.Base(destPath)+resumeInfoSuffix)
+}
+
+// IsTempFile reports whether a destination path is an in-flight or interrupted transfer artifact
+func IsTempFile(path string) bool {
+	if base := filepath.Base(path); strings.HasSuffix(base, tempFileSuffix) || strings.HasSuffix(base, resumeInfoSuffix) {
+		return true
+	}
+	return filepath.Base(filepath.Dir(path)) == partialDirName
+}
+
+// ResumableTarget returns the final destination path of a partial file that a later transfer can resume from:
+// either a file in the rsync partial directory, or a hidden temp file written next to its target (or the
+// record of the source it was copied from)
+func ResumableTarget(path string) (string, bool) {
+	dir, base := filepath.Dir(path), filepath.Base(path)
+	if filepath.Base(dir) == partialDirName {
+		return filepath.Join(filepath.Dir(dir), base), true
+	}
+
+	for _, suffix := range []string{tempFileSuffix, resumeInfoSuffix} {
+		if strings.HasPrefix(base, ".") && strings.HasSuffix(base, suffix) {
+			name := strings.TrimSuffix(strings.TrimPrefix(base, "."), suffix)
+			if name != "" {
+				return filepath.Join(dir, name), true
+			}
+		}
+	}
+	return "", false
+}
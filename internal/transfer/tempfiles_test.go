@@ -0,0 +1,61 @@
+package transfer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResumableTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		wantTarget    string
+		wantResumable bool
+	}{
+		{
+			name:          "rsync partial directory",
+			path:          filepath.Join("/media", "Movies", partialDirName, "Movie.mkv"),
+			wantTarget:    filepath.Join("/media", "Movies", "Movie.mkv"),
+			wantResumable: true,
+		},
+		{
+			name:          "hidden temp file next to target",
+			path:          filepath.Join("/media", "Movies", ".Movie.mkv"+tempFileSuffix),
+			wantTarget:    filepath.Join("/media", "Movies", "Movie.mkv"),
+			wantResumable: true,
+		},
+		{
+			name:          "temp file round trip",
+			path:          tempPathFor(filepath.Join("/media", "Shows", "Show S01E01.mkv")),
+			wantTarget:    filepath.Join("/media", "Shows", "Show S01E01.mkv"),
+			wantResumable: true,
+		},
+		{
+			name:          "resume info next to target",
+			path:          resumeInfoPathFor(filepath.Join("/media", "Movies", "Movie.mkv")),
+			wantTarget:    filepath.Join("/media", "Movies", "Movie.mkv"),
+			wantResumable: true,
+		},
+		{
+			name: "temp suffix without leading dot",
+			path: filepath.Join("/media", "Movies", "Movie.mkv"+tempFileSuffix),
+		},
+		{
+			name: "temp suffix without a name",
+			path: filepath.Join("/media", "Movies", "."+tempFileSuffix),
+		},
+		{
+			name: "regular file",
+			path: filepath.Join("/media", "Movies", "Movie.mkv"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, resumable := ResumableTarget(tt.path)
+			if resumable != tt.wantResumable || target != tt.wantTarget {
+				t.Errorf("ResumableTarget(%q) = (%q, %t), want (%q, %t)", tt.path, target, resumable, tt.wantTarget, tt.wantResumable)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package transfer
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+)
+
+// throttleLogInterval limits how often throttling is reported
+const throttleLogInterval = 10 * time.Second
+
+// ThrottledWriter wraps a writer and limits its throughput, for transfers that copy bytes natively
+type ThrottledWriter struct {
+	writer         io.Writer
+	bytesPerSecond int64
+	logger         *logger.Logger
+
+	mu       sync.Mutex
+	started  time.Time
+	written  int64
+	lastLog  time.Time
+	chunkCap int
+}
+
+// NewThrottledWriter creates a writer limited to bytesPerSecond (0 = unlimited)
+func NewThrottledWriter(w io.Writer, bytesPerSecond int64, log *logger.Logger) *ThrottledWriter {
+	chunkCap := 64 * 1024
+	if bytesPerSecond > 0 && bytesPerSecond/4 < int64(chunkCap) {
+		// Keep chunks small enough that slow limits are still applied smoothly
+		chunkCap = int(bytesPerSecond/4) + 1
+	}
+
+	return &ThrottledWriter{
+		writer:         w,
+		bytesPerSecond: bytesPerSecond,
+		logger:         log,
+		chunkCap:       chunkCap,
+	}
+}
+
+// Write writes p in chunks, sleeping whenever the average rate would exceed the limit
+func (t *ThrottledWriter) Write(p []byte) (int, error) {
+	if t.bytesPerSecond <= 0 {
+		return t.writer.Write(p)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started.IsZero() {
+		t.started = time.Now()
+	}
+
+	total := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > t.chunkCap {
+			chunk = p[:t.chunkCap]
+		}
+
+		n, err := t.writer.Write(chunk)
+		total += n
+		t.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+
+		t.wait()
+	}
+
+	return total, nil
+}
+
+// wait sleeps until the bytes written so far fit within the configured rate
+func (t *ThrottledWriter) wait() {
+	elapsed := time.Since(t.started)
+	expected := time.Duration(float64(t.written) / float64(t.bytesPerSecond) * float64(time.Second))
+	if expected <= elapsed {
+		return
+	}
+
+	if t.logger != nil && elapsed > 0 && time.Since(t.lastLog) >= throttleLogInterval {
+		currentMBps := float64(t.written) / elapsed.Seconds() / (1024 * 1024)
+		limitMBps := float64(t.bytesPerSecond) / (1024 * 1024)
+		t.logger.LogBandwidthThrottled(currentMBps, limitMBps)
+		t.lastLog = time.Now()
+	}
+
+	time.Sleep(expected - elapsed)
+}
@@ -1,7 +1,9 @@
 package transfer
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/nullable-eth/syncarr/internal/config"
 	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/internal/state"
 	"github.com/nullable-eth/syncarr/pkg/types"
 )
 
@@ -19,148 +22,532 @@ type TransferMethod string
 const (
 	TransferMethodSCP   TransferMethod = "scp"
 	TransferMethodRsync TransferMethod = "rsync"
+	TransferMethodLocal TransferMethod = "local" // Destination mounted into the container (NFS/SMB), no SSH
 )
 
+// TransferDirectionPull fetches files from the source host over SSH into a local DEST_ROOT_DIR
+const TransferDirectionPull = "pull"
+
+// ErrTransferWindowClosed is returned when the bandwidth schedule pauses transfers; files not yet started
+// are left for a later cycle
+var ErrTransferWindowClosed = errors.New("transfer window closed")
+
 // FileTransferrer defines the interface for file transfer implementations
 type FileTransferrer interface {
 	TransferFile(sourcePath, destPath string) error
-	TransferFiles(files []types.FileTransfer) error
+	TransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error)
 	Close() error
 	GetFileSize(path string) (int64, error)
 	DeleteFile(path string) error
 	ListDirectoryContents(rootPath string) ([]string, error)
+	ListFileSizes(rootPath string) (map[string]int64, error)
+	GetFreeSpace(path string) (int64, error)
+	Progress() *ProgressTracker
+	TestConnection() error
+	StatSource(path string) (int64, error)
+	ListSourceFiles(dir string) ([]string, error)
 }
 
 // transferImplementation defines the interface for actual transfer implementations (rsync/scp only)
 type transferImplementation interface {
 	doTransferFile(sourcePath, destPath string) error
-	doTransferFiles(files []types.FileTransfer) error
+	doTransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error)
+	setBandwidthLimit(bytesPerSecond int64)
+	batchesFiles() bool // Whether the implementation detects unchanged files itself (no per-file size check needed)
 }
 
 // transferClient is the unified client that handles common logic and delegates to internal implementations
 type transferClient struct {
-	method   TransferMethod
-	fileOps  fileOperations
-	transfer transferImplementation
-	logger   *logger.Logger
+	method    TransferMethod
+	pull      bool           // Source is remote (SSH) and destination local, instead of the other way round
+	sourceOps fileOperations // Source file system: local in push mode, SSH in pull mode
+	fileOps   fileOperations // Destination file system: SSH (or a local mount) in push mode, local in pull mode
+	transfer  transferImplementation
+	verifier  *checksumVerifier // Optional: nil unless checksum verification is enabled
+	progress  *ProgressTracker
+	logger    *logger.Logger
+
+	linkMode       linkMode // Link instead of copying when source and destination share a filesystem
+	linkSourceRoot string
+	destRootDir    string // Destination root, used for connectivity checks and link path mapping
+
+	bandwidthAt    func(time.Time) (int64, bool) // Bandwidth schedule: cap at a time and whether transfers may run
+	bandwidthLimit int64                         // Cap currently applied to the implementation (-1 = none yet)
 }
 
 // newSSHClient creates a new SSH client for file operations
 func newSSHClient(cfg *config.Config, log *logger.Logger) (fileOperations, error) {
 	return &sshClient{
-		sshConfig:    &cfg.SSH,
-		serverConfig: &cfg.Destination,
-		logger:       log,
+		sshConfig: &cfg.SSH,
+		logger:    log,
+		pool:      newSSHPool(&cfg.SSH, log),
 	}, nil
 }
 
 // NewTransferrer creates a new unified file transferrer that automatically chooses the best method
-func NewTransferrer(method TransferMethod, cfg *config.Config, log *logger.Logger) (FileTransferrer, error) {
-	// Create shared SSH client for all file operations
-	sshFileOps, err := newSSHClient(cfg, log)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create SSH client: %w", err)
+func NewTransferrer(method TransferMethod, cfg *config.Config, store *state.Store, log *logger.Logger) (FileTransferrer, error) {
+	// Create shared file operations: SSH reaches the destination when pushing and the source when pulling;
+	// the other side (and a locally mounted destination) is the local filesystem
+	pull := cfg.TransferDirection == TransferDirectionPull
+	sourceFileOps := newLocalFileOps(log)
+	destFileOps := newLocalFileOps(log)
+	if method != TransferMethodLocal {
+		sshFileOps, err := newSSHClient(cfg, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSH client: %w", err)
+		}
+		if pull {
+			sourceFileOps = sshFileOps
+		} else {
+			destFileOps = sshFileOps
+		}
 	}
 
+	// Progress is reported by the transfer implementations while data is moving
+	progress := NewProgressTracker(cfg.Transfer.ProgressLogInterval, log)
+
 	// Create transfer implementation
 	var transferImpl transferImplementation
+	var err error
 
 	switch method {
 	case TransferMethodSCP:
-		transferImpl, err = newSCPTransfer(cfg, log)
+		transferImpl, err = newSCPTransfer(cfg, sourceFileOps, destFileOps, progress, log)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create SCP transferrer: %w", err)
 		}
 	case TransferMethodRsync:
-		transferImpl, err = newRsyncTransfer(cfg, log)
+		transferImpl, err = newRsyncTransfer(cfg, sourceFileOps, progress, log)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create rsync transferrer: %w", err)
 		}
+	case TransferMethodLocal:
+		transferImpl, err = newLocalTransfer(cfg, destFileOps, progress, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local transferrer: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported transfer method: %s", method)
 	}
 
-	log.WithField("transfer_method", string(method)).Info("High-performance file transfer enabled")
+	log.WithFields(map[string]interface{}{
+		"transfer_method": string(method),
+		"pull":            pull,
+	}).Info("High-performance file transfer enabled")
+
+	client := &transferClient{
+		method:    method,
+		pull:      pull,
+		sourceOps: sourceFileOps,
+		fileOps:   destFileOps,
+		transfer:  transferImpl,
+		progress:  progress,
+		logger:    log,
+
+		destRootDir: cfg.DestRootDir,
+
+		bandwidthAt:    cfg.BandwidthAt,
+		bandwidthLimit: -1,
+	}
+
+	if mode := linkMode(cfg.Transfer.LinkMode); mode != "" && mode != linkOff {
+		client.linkMode = mode
+		client.linkSourceRoot = cfg.Transfer.LinkSourceRoot
+		log.WithFields(map[string]interface{}{
+			"link_mode":        string(mode),
+			"link_source_root": cfg.Transfer.LinkSourceRoot,
+		}).Info("Link mode enabled, files on the same filesystem are linked instead of copied")
+	}
 
-	return &transferClient{
-		method:   method,
-		fileOps:  sshFileOps,
-		transfer: transferImpl,
-		logger:   log,
-	}, nil
+	if cfg.Transfer.VerifyChecksums {
+		algorithm := checksumAlgorithm(cfg.Transfer.ChecksumAlgorithm)
+		client.verifier = newChecksumVerifier(algorithm, sourceFileOps, destFileOps, store, log)
+		log.WithField("algorithm", string(algorithm)).Info("Post-transfer checksum verification enabled")
+	}
+
+	return client, nil
 }
 
 // TransferFile handles file transfer with unified logic - checks file existence, size, and delegates to internal implementation
 func (t *transferClient) TransferFile(sourcePath, destPath string) error {
+	if err := t.applyTransferWindow(); err != nil {
+		return err
+	}
+	_, err := t.transferSingle(sourcePath, destPath)
+	return err
+}
+
+// transferSingle transfers one file and reports whether it was transferred or skipped
+func (t *transferClient) transferSingle(sourcePath, destPath string) (types.FileTransferStatus, error) {
 	// Get source file info
-	fileInfo, err := os.Stat(sourcePath)
+	sourceSize, err := t.StatSource(sourcePath)
 	if err != nil {
-		return fmt.Errorf("failed to stat source file: %w", err)
+		return types.FileTransferFailed, fmt.Errorf("failed to stat source file: %w", err)
 	}
 
 	// Check if destination file exists and get its size in one optimized call
 	destSize, err := t.fileOps.GetFileSize(destPath)
-	if err != nil {
+	if errors.Is(err, ErrConnection) {
+		return types.FileTransferFailed, fmt.Errorf("failed to check destination file: %w", err)
+	} else if err != nil {
 		// File doesn't exist or can't be accessed, proceed with transfer
 		t.logger.WithError(err).WithField("dest_path", destPath).Debug("Destination file doesn't exist or can't be accessed, proceeding with transfer")
-	} else if destSize == fileInfo.Size() {
-		// Files are the same size, log skip and return early
-		t.logger.LogTransferSkipped(sourcePath, destPath, fileInfo.Size(), "identical_size")
-		return nil
+	} else if destSize == sourceSize {
+		if t.verifier == nil {
+			// Files are the same size, log skip and return early
+			t.logger.LogTransferSkipped(sourcePath, destPath, sourceSize, "identical_size")
+			t.progress.SkipFile(sourceSize)
+			return types.FileTransferSkipped, nil
+		}
+
+		// Same size is not proof of same content (corruption, same-size re-encodes), compare hashes
+		matches, verifyErr := t.verifier.Verify(sourcePath, destPath)
+		if verifyErr != nil {
+			t.logger.WithError(verifyErr).WithField("dest_path", destPath).Warn("Checksum comparison failed, keeping existing destination file")
+			t.logger.LogTransferSkipped(sourcePath, destPath, sourceSize, "identical_size")
+			t.progress.SkipFile(sourceSize)
+			return types.FileTransferSkipped, nil
+		}
+		if matches {
+			t.logger.LogTransferSkipped(sourcePath, destPath, sourceSize, "checksum_match")
+			t.progress.SkipFile(sourceSize)
+			return types.FileTransferSkipped, nil
+		}
+
+		t.logger.WithFields(map[string]interface{}{
+			"source_path": sourcePath,
+			"dest_path":   destPath,
+		}).Warn("Destination file has the same size but a different checksum, retransferring")
+		if err := t.discardDestination(destPath); err != nil {
+			return types.FileTransferFailed, err
+		}
 	}
 
 	// Ensure destination directory exists before transfer
 	if err := t.ensureDestinationDir(destPath); err != nil {
-		return fmt.Errorf("failed to create destination directory: %w", err)
+		return types.FileTransferFailed, fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	// Same filesystem: a hardlink or reflink replaces the byte copy
+	if t.linkMode != "" && t.tryLink(sourcePath, destPath, sourceSize) {
+		return types.FileTransferTransferred, nil
 	}
 
 	// If we get here, we're actually going to transfer the file
 	startTime := time.Now()
-	t.logger.LogTransferStarted(sourcePath, destPath, fileInfo.Size())
+	t.logger.LogTransferStarted(sourcePath, destPath, sourceSize)
 
 	// Delegate to transfer implementation for actual transfer (directory already created)
 	if err := t.transfer.doTransferFile(sourcePath, destPath); err != nil {
 		// Check if this is a special "file was skipped" error
 		if strings.Contains(err.Error(), "file_skipped") {
 			// File was skipped by rsync (already up-to-date), log as skipped
-			t.logger.LogTransferSkipped(sourcePath, destPath, fileInfo.Size(), "rsync_skipped")
-			return nil
+			t.logger.LogTransferSkipped(sourcePath, destPath, sourceSize, "rsync_skipped")
+			return types.FileTransferSkipped, nil
+		}
+		return types.FileTransferFailed, fmt.Errorf("transfer failed using %s: %w", t.method, err)
+	}
+
+	if t.verifier != nil {
+		if err := t.verifyTransferred(sourcePath, destPath); err != nil {
+			return types.FileTransferFailed, err
 		}
-		return fmt.Errorf("transfer failed using %s: %w", t.method, err)
 	}
 
 	// Log successful completion
 	duration := time.Since(startTime)
-	t.logger.LogTransferCompleted(sourcePath, destPath, fileInfo.Size(), duration)
+	t.logger.LogTransferCompleted(sourcePath, destPath, sourceSize, duration)
+
+	return types.FileTransferTransferred, nil
+}
+
+// verifyTransferred checks a freshly transferred file and retransfers it once if the checksums differ
+func (t *transferClient) verifyTransferred(sourcePath, destPath string) error {
+	defer func() {
+		if err := t.verifier.Save(); err != nil {
+			t.logger.WithError(err).Warn("Failed to persist checksum cache")
+		}
+	}()
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		matches, err := t.verifier.Verify(sourcePath, destPath)
+		if err != nil {
+			return fmt.Errorf("checksum verification failed: %w", err)
+		}
+		if matches {
+			t.logger.WithFields(map[string]interface{}{
+				"dest_path": destPath,
+				"attempt":   attempt,
+			}).Debug("Transferred file passed checksum verification")
+			return nil
+		}
+
+		t.logger.WithFields(map[string]interface{}{
+			"source_path": sourcePath,
+			"dest_path":   destPath,
+			"attempt":     attempt,
+		}).Warn("Transferred file failed checksum verification")
+
+		if attempt == 2 {
+			break
+		}
 
+		// Remove the corrupt copy so the transfer tool cannot consider it up to date
+		if err := t.discardDestination(destPath); err != nil {
+			return err
+		}
+		if err := t.transfer.doTransferFile(sourcePath, destPath); err != nil {
+			return fmt.Errorf("retransfer after checksum mismatch failed using %s: %w", t.method, err)
+		}
+	}
+
+	return fmt.Errorf("checksum mismatch persists after retransfer: %s", destPath)
+}
+
+// discardDestination deletes a destination file whose content is known to be wrong
+func (t *transferClient) discardDestination(destPath string) error {
+	if err := t.fileOps.DeleteFile(destPath); err != nil {
+		return fmt.Errorf("failed to delete mismatched destination file: %w", err)
+	}
+	t.verifier.Invalidate(destPath)
 	return nil
 }
 
-// TransferFiles transfers multiple files (delegates to transfer implementation)
-func (t *transferClient) TransferFiles(files []types.FileTransfer) error {
-	return t.transfer.doTransferFiles(files)
+// TransferFiles transfers a batch of files and returns a result for every file. Implementations that
+// detect unchanged files themselves (rsync) receive the batch one destination directory at a time, without
+// per-file size checks. The bandwidth schedule is re-evaluated before every file or directory; when it pauses
+// transfers, the results so far are returned with ErrTransferWindowClosed.
+func (t *transferClient) TransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error) {
+	results := make([]types.FileTransferResult, 0, len(files))
+
+	// Linking is decided per file, so batching is bypassed while a link mode is active
+	if !t.transfer.batchesFiles() || t.linkMode != "" {
+		for _, file := range files {
+			if err := t.applyTransferWindow(); err != nil {
+				return results, err
+			}
+			status, err := t.transferSingle(file.SourcePath, file.DestPath)
+			result := types.FileTransferResult{SourcePath: file.SourcePath, DestPath: file.DestPath, Size: file.Size, Status: status}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	}
+
+	// Stat sources and create each destination directory once
+	var pending []types.FileTransfer
+	createdDirs := make(map[string]bool)
+	for _, file := range files {
+		sourceSize, err := t.StatSource(file.SourcePath)
+		if err != nil {
+			results = append(results, newFileTransferResult(file, false, fmt.Errorf("failed to stat source file: %w", err)))
+			continue
+		}
+		file.Size = sourceSize
+
+		if destDir := filepath.Dir(file.DestPath); !createdDirs[destDir] {
+			if err := t.fileOps.CreateDirectory(destDir); err != nil {
+				results = append(results, newFileTransferResult(file, false, fmt.Errorf("failed to create destination directory: %w", err)))
+				continue
+			}
+			createdDirs[destDir] = true
+		}
+
+		pending = append(pending, file)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	// Group by destination directory, keeping the batch order, so a closing window stops between directories
+	var dirs []string
+	groups := make(map[string][]types.FileTransfer)
+	for _, file := range pending {
+		dir := filepath.Dir(file.DestPath)
+		if _, exists := groups[dir]; !exists {
+			dirs = append(dirs, dir)
+		}
+		groups[dir] = append(groups[dir], file)
+	}
+
+	startTime := time.Now()
+	var batchResults []types.FileTransferResult
+	var windowErr error
+	for _, dir := range dirs {
+		if windowErr = t.applyTransferWindow(); windowErr != nil {
+			break
+		}
+		groupResults, err := t.transfer.doTransferFiles(groups[dir])
+		if err != nil {
+			return append(results, batchResults...), fmt.Errorf("batch transfer failed using %s: %w", t.method, err)
+		}
+		batchResults = append(batchResults, groupResults...)
+	}
+
+	var transferredCount, skippedCount, failedCount int
+	var transferredBytes int64
+	for i := range batchResults {
+		result := &batchResults[i]
+
+		if t.verifier != nil && result.Status != types.FileTransferFailed {
+			t.verifyBatchResult(result)
+		}
+
+		switch result.Status {
+		case types.FileTransferTransferred:
+			transferredCount++
+			transferredBytes += result.Size
+		case types.FileTransferSkipped:
+			skippedCount++
+			t.logger.LogTransferSkipped(result.SourcePath, result.DestPath, result.Size, "rsync_skipped")
+		case types.FileTransferFailed:
+			failedCount++
+		}
+	}
+	results = append(results, batchResults...)
+
+	if transferredCount > 0 {
+		duration := time.Since(startTime)
+		t.logger.WithFields(map[string]interface{}{
+			"event":             "batch_transfer_completed",
+			"files_transferred": transferredCount,
+			"files_skipped":     skippedCount,
+			"files_failed":      failedCount,
+			"size_mb":           math.Round(float64(transferredBytes)/(1024*1024)*10) / 10,
+			"rate_mbps":         math.Round(float64(transferredBytes)/(1024*1024)/duration.Seconds()*10) / 10,
+			"duration_sec":      math.Round(duration.Seconds()*10) / 10,
+		}).Info("Batch file transfer completed")
+	}
+
+	return results, windowErr
+}
+
+// verifyBatchResult applies checksum verification to a file handled by a batch transfer,
+// retransferring it individually when the destination content differs
+func (t *transferClient) verifyBatchResult(result *types.FileTransferResult) {
+	var err error
+	if result.Status == types.FileTransferTransferred {
+		err = t.verifyTransferred(result.SourcePath, result.DestPath)
+	} else {
+		var matches bool
+		matches, err = t.verifier.Verify(result.SourcePath, result.DestPath)
+		if err == nil && !matches {
+			t.logger.WithField("dest_path", result.DestPath).Warn("Destination file is up to date by size and time but has a different checksum, retransferring")
+			if err = t.discardDestination(result.DestPath); err == nil {
+				if err = t.transfer.doTransferFile(result.SourcePath, result.DestPath); err == nil {
+					result.Status = types.FileTransferTransferred
+					err = t.verifyTransferred(result.SourcePath, result.DestPath)
+				}
+			}
+		} else if err != nil {
+			// Same behaviour as single transfers: keep the existing file when hashing is not possible
+			t.logger.WithError(err).WithField("dest_path", result.DestPath).Warn("Checksum comparison failed, keeping existing destination file")
+			err = nil
+		}
+	}
+
+	if err != nil {
+		result.Status = types.FileTransferFailed
+		result.Error = err.Error()
+	}
 }
 
-// Close closes the SSH connection
+// Close persists the checksum cache and closes the SSH connections
 func (t *transferClient) Close() error {
+	if t.verifier != nil {
+		if err := t.verifier.Save(); err != nil {
+			t.logger.WithError(err).Warn("Failed to persist checksum cache")
+		}
+	}
 	return t.fileOps.Close()
 }
 
-// GetFileSize gets the size of a file on the destination (via SSH)
+// applyTransferWindow applies the bandwidth schedule before a file or directory group starts, so schedule
+// windows take effect mid-item. It returns ErrTransferWindowClosed while the schedule pauses transfers.
+func (t *transferClient) applyTransferWindow() error {
+	limit, allowed := t.bandwidthAt(time.Now())
+	if !allowed {
+		return ErrTransferWindowClosed
+	}
+	if limit != t.bandwidthLimit {
+		t.transfer.setBandwidthLimit(limit)
+		t.bandwidthLimit = limit
+		t.logger.WithFields(map[string]interface{}{
+			"limit_bytes_per_sec": limit,
+			"unlimited":           limit == 0,
+		}).Debug("Applied transfer bandwidth limit")
+	}
+	return nil
+}
+
+// Progress returns the tracker reporting live transfer progress
+func (t *transferClient) Progress() *ProgressTracker {
+	return t.progress
+}
+
+// TestConnection checks that the destination file system is reachable. Over SSH a missing DEST_ROOT_DIR is
+// fine (it is created on first transfer); a local mount must already exist, otherwise the mount is missing.
+func (t *transferClient) TestConnection() error {
+	// Pulling: the source host must answer over SSH (its home directory always exists)
+	if t.pull {
+		if _, err := t.sourceOps.StatFile("."); err != nil {
+			return fmt.Errorf("source file system unavailable: %w", err)
+		}
+	}
+
+	_, err := t.fileOps.StatFile(t.destRootDir)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, ErrRemoteNotFound) && t.method != TransferMethodLocal && !t.pull {
+		return nil
+	}
+	return fmt.Errorf("destination file system unavailable: %w", err)
+}
+
+// StatSource returns the size of a source file (locally, or on the source host in pull mode).
+// A missing file matches ErrRemoteNotFound.
+func (t *transferClient) StatSource(path string) (int64, error) {
+	info, err := t.sourceOps.StatFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+// ListSourceFiles returns the names of the files directly inside a source directory
+func (t *transferClient) ListSourceFiles(dir string) ([]string, error) {
+	return t.sourceOps.ListFiles(dir)
+}
+
+// GetFileSize gets the size of a file on the destination (via SFTP or the local mount)
 func (t *transferClient) GetFileSize(path string) (int64, error) {
 	return t.fileOps.GetFileSize(path)
 }
 
-// DeleteFile deletes a file on the destination (via SSH)
+// DeleteFile deletes a file on the destination (via SFTP or the local mount)
 func (t *transferClient) DeleteFile(path string) error {
 	return t.fileOps.DeleteFile(path)
 }
 
-// ListDirectoryContents lists directory contents on the destination (via SSH)
+// ListDirectoryContents lists directory contents on the destination (via SFTP or the local mount)
 func (t *transferClient) ListDirectoryContents(rootPath string) ([]string, error) {
 	return t.fileOps.ListDirectoryContents(rootPath)
 }
 
+// ListFileSizes recursively lists files with their sizes on the destination
+func (t *transferClient) ListFileSizes(rootPath string) (map[string]int64, error) {
+	return t.fileOps.ListFileSizes(rootPath)
+}
+
+// GetFreeSpace returns the available bytes on the destination filesystem holding path
+func (t *transferClient) GetFreeSpace(path string) (int64, error) {
+	return t.fileOps.GetFreeSpace(path)
+}
+
 // ensureDestinationDir creates the destination directory using SSH
 func (t *transferClient) ensureDestinationDir(destPath string) error {
 	destDir := filepath.Dir(destPath)
@@ -182,9 +569,9 @@ func GetOptimalTransferMethod(log *logger.Logger) TransferMethod {
 }
 
 // ForceTransferMethod forces a specific transfer method and creates a transfer client (useful for testing)
-func ForceTransferMethod(method TransferMethod, cfg *config.Config, log *logger.Logger) (FileTransferrer, error) {
+func ForceTransferMethod(method TransferMethod, cfg *config.Config, store *state.Store, log *logger.Logger) (FileTransferrer, error) {
 	log.WithField("forced_method", string(method)).Info("Using forced transfer method")
-	return NewTransferrer(method, cfg, log)
+	return NewTransferrer(method, cfg, store, log)
 }
 
 // IsRsyncAvailable checks if rsync is installed and available locally
@@ -0,0 +1,81 @@
+package transfer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nullable-eth/syncarr/internal/logger"
+	"github.com/nullable-eth/syncarr/pkg/types"
+)
+
+// recordingTransfer is a transfer implementation that records the batches and bandwidth limits it receives
+type recordingTransfer struct {
+	batches [][]types.FileTransfer
+	limits  []int64
+}
+
+func (r *recordingTransfer) doTransferFile(sourcePath, destPath string) error { return nil }
+
+func (r *recordingTransfer) doTransferFiles(files []types.FileTransfer) ([]types.FileTransferResult, error) {
+	r.batches = append(r.batches, files)
+	results := make([]types.FileTransferResult, 0, len(files))
+	for _, file := range files {
+		results = append(results, newFileTransferResult(file, true, nil))
+	}
+	return results, nil
+}
+
+func (r *recordingTransfer) setBandwidthLimit(bytesPerSecond int64) {
+	r.limits = append(r.limits, bytesPerSecond)
+}
+
+func (r *recordingTransfer) batchesFiles() bool { return true }
+
+func TestTransferFilesChecksWindowPerDirectory(t *testing.T) {
+	sourceDir, destDir := t.TempDir(), t.TempDir()
+	var files []types.FileTransfer
+	for _, name := range []string{"Show/S01/E01.mkv", "Show/S01/E01.en.srt", "Show/S02/E01.mkv"} {
+		sourcePath := filepath.Join(sourceDir, name)
+		if err := os.MkdirAll(filepath.Dir(sourcePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(sourcePath, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, types.FileTransfer{SourcePath: sourcePath, DestPath: filepath.Join(destDir, name)})
+	}
+
+	// The window is open at a capped rate for the first directory and closes before the second
+	checks := 0
+	log := logger.New("error")
+	impl := &recordingTransfer{}
+	client := &transferClient{
+		sourceOps:      newLocalFileOps(log),
+		fileOps:        newLocalFileOps(log),
+		transfer:       impl,
+		progress:       NewProgressTracker(0, log),
+		logger:         log,
+		bandwidthLimit: -1,
+		bandwidthAt: func(time.Time) (int64, bool) {
+			checks++
+			return 1024, checks == 1
+		},
+	}
+
+	results, err := client.TransferFiles(files)
+	if !errors.Is(err, ErrTransferWindowClosed) {
+		t.Fatalf("TransferFiles() error = %v, want ErrTransferWindowClosed", err)
+	}
+	if len(impl.batches) != 1 || len(impl.batches[0]) != 2 {
+		t.Fatalf("transferred batches = %v, want only the two files of the first directory", impl.batches)
+	}
+	if len(results) != 2 {
+		t.Errorf("got %d results, want 2", len(results))
+	}
+	if len(impl.limits) != 1 || impl.limits[0] != 1024 {
+		t.Errorf("applied limits = %v, want [1024]", impl.limits)
+	}
+}
@@ -37,6 +37,24 @@ type FileTransfer struct {
 	Size       int64  `json:"size"`
 }
 
+// FileTransferStatus describes the outcome of a single file in a transfer batch
+type FileTransferStatus string
+
+const (
+	FileTransferTransferred FileTransferStatus = "transferred"
+	FileTransferSkipped     FileTransferStatus = "skipped" // Already up to date on the destination
+	FileTransferFailed      FileTransferStatus = "failed"
+)
+
+// FileTransferResult represents the per-file result of a batch transfer
+type FileTransferResult struct {
+	SourcePath string             `json:"sourcePath"`
+	DestPath   string             `json:"destPath"`
+	Size       int64              `json:"size"`
+	Status     FileTransferStatus `json:"status"`
+	Error      string             `json:"error,omitempty"`
+}
+
 // SyncError represents a synchronization error
 type SyncError struct {
 	Type        string                 `json:"type"`